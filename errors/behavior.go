@@ -0,0 +1,223 @@
+package errors
+
+// TemporaryError is implemented by errors that know whether the condition
+// causing them is temporary, such as a full queue or a lost connection.
+type TemporaryError interface {
+	error
+	Temporary() bool
+}
+
+// TimeoutError is implemented by errors that know whether they are caused by a
+// timeout. context.DeadlineExceeded and the net package's timeout errors
+// implement it.
+type TimeoutError interface {
+	error
+	Timeout() bool
+}
+
+// WarningError is implemented by errors that know whether they are only
+// warnings: conditions worth reporting that do not make the operation fail,
+// such as a deprecated option or a partially applied update.
+type WarningError interface {
+	error
+	Warning() bool
+}
+
+// RetryableError is implemented by errors that know whether the operation that
+// returned them can be retried.
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+type temporaryError struct {
+	wrapper
+
+	temporary bool
+}
+
+func (e *temporaryError) Temporary() bool { return e.temporary }
+
+type timeoutError struct {
+	wrapper
+
+	timeout bool
+}
+
+func (e *timeoutError) Timeout() bool { return e.timeout }
+
+type warningError struct {
+	wrapper
+
+	warning bool
+}
+
+func (e *warningError) Warning() bool { return e.warning }
+
+type retryableError struct {
+	wrapper
+
+	retryable bool
+}
+
+func (e *retryableError) Retryable() bool { return e.retryable }
+
+// WithTemporary marks err as temporary or not, overriding what the errors it
+// wraps report. The message is left unchanged and the result unwraps to err.
+// If err is nil, WithTemporary returns nil.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusServiceUnavailable {
+//	    return errors.WithTemporary(ErrUnavailable, true)
+//	}
+func WithTemporary(err error, temporary bool) error {
+	if err == nil {
+		return nil
+	}
+	return &temporaryError{wrapper{err}, temporary}
+}
+
+// WithTimeout marks err as caused by a timeout or not, overriding what the
+// errors it wraps report. The message is left unchanged and the result unwraps
+// to err. If err is nil, WithTimeout returns nil.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusGatewayTimeout {
+//	    return errors.WithTimeout(ErrUpstream, true)
+//	}
+func WithTimeout(err error, timeout bool) error {
+	if err == nil {
+		return nil
+	}
+	return &timeoutError{wrapper{err}, timeout}
+}
+
+// WithWarning marks err as a warning or not, overriding what the errors it
+// wraps report. The message is left unchanged and the result unwraps to err.
+// If err is nil, WithWarning returns nil.
+//
+// Example:
+//
+//	if len(skipped) > 0 {
+//	    return errors.WithWarning(fmt.Errorf("%d rows skipped", len(skipped)), true)
+//	}
+func WithWarning(err error, warning bool) error {
+	if err == nil {
+		return nil
+	}
+	return &warningError{wrapper{err}, warning}
+}
+
+// WithRetryable marks err as retryable or not, overriding what the errors it
+// wraps report. Retry treats errors marked as not retryable as Permanent.
+// The message is left unchanged and the result unwraps to err.
+// If err is nil, WithRetryable returns nil.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusBadRequest {
+//	    return errors.WithRetryable(ErrBadRequest, false)
+//	}
+func WithRetryable(err error, retryable bool) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{wrapper{err}, retryable}
+}
+
+// behaviorOf returns the value reported by the outermost error in err's tree
+// that implements T, and whether one was found.
+func behaviorOf[T error](err error, get func(T) bool) (value, found bool) {
+	walk(err, func(e error) bool {
+		if b, ok := e.(T); ok {
+			value, found = get(b), true
+			return false
+		}
+		return true
+	})
+	return value, found
+}
+
+// IsTemporary reports whether err is temporary, according to the outermost
+// error in its tree implementing TemporaryError. It returns false if there is none.
+// IsTemporary can be used as a Condition.
+func IsTemporary(err error) bool {
+	temporary, _ := behaviorOf(err, TemporaryError.Temporary)
+	return temporary
+}
+
+// IsTimeout reports whether err is caused by a timeout, according to the
+// outermost error in its tree implementing TimeoutError. It returns false if
+// there is none. IsTimeout can be used as a Condition.
+//
+// Example:
+//
+//	errors.IsTimeout(fmt.Errorf("query: %w", context.DeadlineExceeded)) // true
+func IsTimeout(err error) bool {
+	timeout, _ := behaviorOf(err, TimeoutError.Timeout)
+	return timeout
+}
+
+// IsWarning reports whether err is only a warning, according to the outermost
+// error in its tree implementing WarningError. It returns false if there is
+// none. IsWarning can be used as a Condition.
+func IsWarning(err error) bool {
+	warning, _ := behaviorOf(err, WarningError.Warning)
+	return warning
+}
+
+// IsRetryable reports whether the operation that returned err can be retried.
+// The outermost error in err's tree implementing RetryableError decides;
+// if there is none, temporary errors and timeouts are retryable.
+// IsRetryable can be used as a Condition.
+//
+// Example:
+//
+//	temporary := errors.WithTemporary(err, true)
+//	errors.IsRetryable(temporary)                              // true
+//	errors.IsRetryable(errors.WithRetryable(temporary, false)) // false
+func IsRetryable(err error) bool {
+	if retryable, ok := behaviorOf(err, RetryableError.Retryable); ok {
+		return retryable
+	}
+	return IsTemporary(err) || IsTimeout(err)
+}
+
+// OnTemporary creates an ErrorMatcher for temporary errors, as reported by IsTemporary.
+//
+// Example:
+//
+//	handled, result := Handle(err,
+//	    OnTemporary(func(e error) error {
+//	        return queue.Requeue(job)
+//	    }),
+//	)
+func OnTemporary(handler ErrorHandler) ErrorMatcher {
+	return On(IsTemporary, handler)
+}
+
+// OnTimeout creates an ErrorMatcher for timeouts, as reported by IsTimeout.
+func OnTimeout(handler ErrorHandler) ErrorMatcher {
+	return On(IsTimeout, handler)
+}
+
+// OnWarning creates an ErrorMatcher for warnings, as reported by IsWarning.
+//
+// Example:
+//
+//	handled, result := Handle(err,
+//	    OnWarning(func(e error) error {
+//	        logger.Warn("import finished with warnings", "err", e)
+//	        return nil
+//	    }),
+//	)
+func OnWarning(handler ErrorHandler) ErrorMatcher {
+	return On(IsWarning, handler)
+}
+
+// OnRetryable creates an ErrorMatcher for retryable errors, as reported by IsRetryable.
+func OnRetryable(handler ErrorHandler) ErrorMatcher {
+	return On(IsRetryable, handler)
+}
@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type flakyError struct{}
+
+func (flakyError) Error() string   { return "flaky" }
+func (flakyError) Temporary() bool { return true }
+
+func TestBehaviorWrappers(t *testing.T) {
+	wrappers := map[string]func(error, bool) error{
+		"WithTemporary": WithTemporary,
+		"WithTimeout":   WithTimeout,
+		"WithWarning":   WithWarning,
+		"WithRetryable": WithRetryable,
+	}
+	for name, wrap := range wrappers {
+		t.Run(name+" with nil error", func(t *testing.T) {
+			assert.NoError(t, wrap(nil, true))
+		})
+
+		t.Run(name+" keeps the message and chain", func(t *testing.T) {
+			err := wrap(fmt.Errorf("read: %w", io.EOF), true)
+
+			require.ErrorIs(t, err, io.EOF)
+			assert.Equal(t, "read: EOF", err.Error())
+			assert.Equal(t, "read: EOF", fmt.Sprintf("%v", err))
+		})
+	}
+}
+
+func TestIsTemporary(t *testing.T) {
+	t.Run("IsTemporary with nil error", func(t *testing.T) {
+		assert.False(t, IsTemporary(nil))
+	})
+
+	t.Run("IsTemporary with plain error", func(t *testing.T) {
+		assert.False(t, IsTemporary(io.EOF))
+	})
+
+	t.Run("IsTemporary with marked error", func(t *testing.T) {
+		assert.True(t, IsTemporary(fmt.Errorf("send: %w", WithTemporary(io.EOF, true))))
+	})
+
+	t.Run("IsTemporary with custom error type", func(t *testing.T) {
+		assert.True(t, IsTemporary(fmt.Errorf("send: %w", flakyError{})))
+	})
+
+	t.Run("IsTemporary outermost mark wins", func(t *testing.T) {
+		assert.False(t, IsTemporary(WithTemporary(flakyError{}, false)))
+	})
+
+	t.Run("IsTemporary with joined errors", func(t *testing.T) {
+		assert.True(t, IsTemporary(errors.Join(io.EOF, flakyError{})))
+	})
+}
+
+func TestIsTimeout(t *testing.T) {
+	t.Run("IsTimeout with plain error", func(t *testing.T) {
+		assert.False(t, IsTimeout(io.EOF))
+	})
+
+	t.Run("IsTimeout with standard library timeouts", func(t *testing.T) {
+		assert.True(t, IsTimeout(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+		assert.True(t, IsTimeout(os.ErrDeadlineExceeded))
+	})
+
+	t.Run("IsTimeout with marked error", func(t *testing.T) {
+		assert.True(t, IsTimeout(WithTimeout(io.EOF, true)))
+		assert.False(t, IsTimeout(WithTimeout(context.DeadlineExceeded, false)))
+	})
+}
+
+func TestIsWarning(t *testing.T) {
+	t.Run("IsWarning with plain error", func(t *testing.T) {
+		assert.False(t, IsWarning(io.EOF))
+	})
+
+	t.Run("IsWarning with marked error", func(t *testing.T) {
+		err := fmt.Errorf("import: %w", WithWarning(New("3 rows skipped"), true))
+		assert.True(t, IsWarning(err))
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Run("IsRetryable with plain error", func(t *testing.T) {
+		assert.False(t, IsRetryable(io.EOF))
+	})
+
+	t.Run("IsRetryable with temporary errors and timeouts", func(t *testing.T) {
+		assert.True(t, IsRetryable(flakyError{}))
+		assert.True(t, IsRetryable(context.DeadlineExceeded))
+	})
+
+	t.Run("IsRetryable with marked error", func(t *testing.T) {
+		assert.True(t, IsRetryable(WithRetryable(io.EOF, true)))
+		assert.False(t, IsRetryable(WithRetryable(flakyError{}, false)))
+		assert.False(t, IsRetryable(WithTemporary(WithRetryable(io.EOF, false), true)))
+	})
+}
+
+func TestBehaviorMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher func(ErrorHandler) ErrorMatcher
+		match   error
+	}{
+		{"OnTemporary", OnTemporary, WithTemporary(io.EOF, true)},
+		{"OnTimeout", OnTimeout, context.DeadlineExceeded},
+		{"OnWarning", OnWarning, WithWarning(io.EOF, true)},
+		{"OnRetryable", OnRetryable, WithRetryable(io.EOF, true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" with matching error", func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tt.match)
+			handled, result := HandleError(err, tt.matcher(func(e error) error { return nil }))
+
+			assert.True(t, handled)
+			assert.NoError(t, result)
+		})
+
+		t.Run(tt.name+" with other error", func(t *testing.T) {
+			handled, result := HandleError(io.EOF, tt.matcher(func(e error) error { return nil }))
+
+			assert.False(t, handled)
+			require.ErrorIs(t, result, io.EOF)
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// Default values used by NewBreaker for zero fields of BreakerOptions.
+const (
+	defaultBreakerThreshold   = 5
+	defaultBreakerWindow      = time.Minute
+	defaultBreakerOpenTimeout = 30 * time.Second
+	defaultBreakerProbes      = 1
+)
+
+// ErrCircuitOpen is returned by Breaker.Do, instead of calling the function,
+// while the circuit is open. The returned error also carries the Unavailable
+// kind and a RetryAfter hint of the time left until the circuit half-opens.
+const ErrCircuitOpen = Const("circuit breaker is open")
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through and counts failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every call with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe calls through to decide
+	// whether to close or reopen the circuit.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state, such as "half-open".
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerOptions configures a Breaker.
+// Zero values select the defaults noted on each field.
+type BreakerOptions struct {
+	// FailureThreshold is the number of failures within Window that opens the
+	// circuit. Defaults to 5.
+	FailureThreshold int
+	// Window is the rolling window failures are counted over. Defaults to 1m.
+	Window time.Duration
+	// OpenTimeout is how long the circuit stays open before half-opening.
+	// Defaults to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe calls let through while half-open,
+	// all of which must succeed to close the circuit. Defaults to 1.
+	HalfOpenProbes int
+	// Matchers classify errors, as Handle would: an error that a matcher handles
+	// with a nil result is not a failure and does not count towards opening the
+	// circuit. Any other error is a failure.
+	Matchers []ErrorMatcher
+	// OnStateChange, if set, is called after every state change. It is called
+	// without holding the breaker's lock, so it may call the breaker.
+	OnStateChange func(from, to BreakerState)
+	// Clock is used to measure windows and timeouts. Nil means the system clock.
+	Clock Clock
+}
+
+// Breaker is a circuit breaker. It lets calls through while failures are rare,
+// rejects them for a while once failures pile up, and then probes whether the
+// dependency has recovered. Which errors count as failures is decided by a set
+// of matchers, so that errors such as cancellations or invalid input do not
+// trip it.
+//
+// A Breaker is safe for concurrent use and must be created with NewBreaker.
+type Breaker struct {
+	opts BreakerOptions
+
+	mu         sync.Mutex
+	state      BreakerState
+	generation uint64      // Incremented on every state change
+	failures   []time.Time // Failure times within the window, oldest first
+	openedAt   time.Time
+	probes     int // Probes in flight while half-open
+	successes  int // Successful probes while half-open
+}
+
+// NewBreaker returns a closed Breaker.
+//
+// Example:
+//
+//	breaker := errors.NewBreaker(errors.BreakerOptions{
+//	    FailureThreshold: 10,
+//	    Window:           time.Minute,
+//	    OpenTimeout:      15 * time.Second,
+//	    Matchers: []errors.ErrorMatcher{
+//	        errors.OnCanceled(func(e error) error { return nil }),
+//	        errors.OnType(func(e *ValidationError) error { return nil }),
+//	    },
+//	    OnStateChange: func(from, to errors.BreakerState) {
+//	        logger.Warn("circuit breaker", "from", from, "to", to)
+//	    },
+//	})
+func NewBreaker(opts BreakerOptions) *Breaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaultBreakerThreshold
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultBreakerWindow
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if opts.HalfOpenProbes <= 0 {
+		opts.HalfOpenProbes = defaultBreakerProbes
+	}
+	opts.Clock = clockOrSystem(opts.Clock)
+	return &Breaker{opts: opts}
+}
+
+// callOutcome is how a Breaker counts the result of a call.
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	callIgnored // An error the matchers handled with a nil result
+)
+
+// stateChange is a state transition to report to OnStateChange.
+type stateChange struct {
+	from, to BreakerState
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	change := b.refresh(b.opts.Clock.Now())
+	state := b.state
+	b.mu.Unlock()
+
+	b.notify(change)
+	return state
+}
+
+// Do calls fn if the circuit allows it and records the outcome.
+// It returns fn's error unchanged, or, without calling fn, an error matching
+// ErrCircuitOpen while the circuit is open or while the half-open probes are
+// all in flight. A panic in fn is recorded as a failure and then propagated.
+//
+// Example:
+//
+//	err := breaker.Do(func() error {
+//	    return client.Send(ctx, msg)
+//	})
+//	if errors.Is(err, errors.ErrCircuitOpen) {
+//	    return fallback(msg)
+//	}
+func (b *Breaker) Do(fn func() error) (err error) {
+	generation, rejected := b.allow()
+	if rejected != nil {
+		return rejected
+	}
+
+	panicked := true
+	defer func() {
+		if panicked {
+			b.record(generation, callFailed)
+		}
+	}()
+	err = fn()
+	panicked = false
+
+	b.record(generation, b.classify(err))
+	return err
+}
+
+// classify returns the outcome of a call that returned err, according to the matchers.
+func (b *Breaker) classify(err error) callOutcome {
+	if err == nil {
+		return callSucceeded
+	}
+	if handled, result := HandleError(err, b.opts.Matchers...); handled && result == nil {
+		return callIgnored
+	}
+	return callFailed
+}
+
+// allow decides whether a call may proceed. It returns the generation the call
+// belongs to, or the error to reject the call with.
+func (b *Breaker) allow() (uint64, error) {
+	now := b.opts.Clock.Now()
+
+	b.mu.Lock()
+	change := b.refresh(now)
+	var rejected error
+	switch b.state {
+	case BreakerClosed:
+	case BreakerOpen:
+		rejected = WithRetryAfter(WithKind(ErrCircuitOpen, Unavailable), b.openedAt.Add(b.opts.OpenTimeout).Sub(now))
+	case BreakerHalfOpen:
+		if b.probes >= b.opts.HalfOpenProbes {
+			rejected = WithKind(ErrCircuitOpen, Unavailable)
+		} else {
+			b.probes++
+		}
+	}
+	generation := b.generation
+	b.mu.Unlock()
+
+	b.notify(change)
+	return generation, rejected
+}
+
+// record updates the breaker with the outcome of a call. Outcomes of calls
+// allowed in an earlier state are ignored, and so are ignored errors, except
+// that they free their probe slot.
+func (b *Breaker) record(generation uint64, outcome callOutcome) {
+	now := b.opts.Clock.Now()
+
+	b.mu.Lock()
+	var change *stateChange
+	if generation == b.generation {
+		switch b.state {
+		case BreakerClosed:
+			if outcome == callFailed {
+				change = b.recordFailure(now)
+			}
+		case BreakerHalfOpen:
+			b.probes--
+			switch {
+			case outcome == callIgnored:
+			case outcome == callFailed:
+				change = b.setState(BreakerOpen, now)
+			case b.successes+1 >= b.opts.HalfOpenProbes:
+				change = b.setState(BreakerClosed, now)
+			default:
+				b.successes++
+			}
+		case BreakerOpen:
+		}
+	}
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// recordFailure adds a failure in the closed state and opens the circuit if
+// the threshold is reached.
+func (b *Breaker) recordFailure(now time.Time) *stateChange {
+	cutoff := now.Add(-b.opts.Window)
+	i := 0
+	for i < len(b.failures) && !b.failures[i].After(cutoff) {
+		i++
+	}
+	b.failures = append(b.failures[i:], now)
+
+	if len(b.failures) >= b.opts.FailureThreshold {
+		return b.setState(BreakerOpen, now)
+	}
+	return nil
+}
+
+// refresh half-opens the circuit if it has been open for long enough.
+func (b *Breaker) refresh(now time.Time) *stateChange {
+	if b.state == BreakerOpen && !now.Before(b.openedAt.Add(b.opts.OpenTimeout)) {
+		return b.setState(BreakerHalfOpen, now)
+	}
+	return nil
+}
+
+// setState moves the breaker to state and resets the state-specific counters.
+func (b *Breaker) setState(state BreakerState, now time.Time) *stateChange {
+	change := &stateChange{from: b.state, to: state}
+	b.state = state
+	b.generation++
+	b.failures = nil
+	b.probes = 0
+	b.successes = 0
+	if state == BreakerOpen {
+		b.openedAt = now
+	}
+	return change
+}
+
+// notify reports change to OnStateChange, if both are set.
+func (b *Breaker) notify(change *stateChange) {
+	if change != nil && b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(change.from, change.to)
+	}
+}
@@ -0,0 +1,253 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stateRecorder struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+func (r *stateRecorder) record(from, to BreakerState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, from.String()+"->"+to.String())
+}
+
+func (r *stateRecorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.changes...)
+}
+
+func failN(b *Breaker, n int, err error) {
+	for range n {
+		_ = b.Do(func() error { return err })
+	}
+}
+
+func TestBreakerState(t *testing.T) {
+	assert.Equal(t, "closed", BreakerClosed.String())
+	assert.Equal(t, "open", BreakerOpen.String())
+	assert.Equal(t, "half-open", BreakerHalfOpen.String())
+	assert.Equal(t, "unknown", BreakerState(42).String())
+}
+
+func TestBreaker(t *testing.T) {
+	errBackend := New("backend down")
+
+	t.Run("Breaker opens at the failure threshold", func(t *testing.T) {
+		clock := newFakeClock()
+		recorder := &stateRecorder{}
+		b := NewBreaker(BreakerOptions{FailureThreshold: 3, Clock: clock, OnStateChange: recorder.record})
+
+		failN(b, 2, errBackend)
+		assert.Equal(t, BreakerClosed, b.State())
+
+		failN(b, 1, errBackend)
+		assert.Equal(t, BreakerOpen, b.State())
+		assert.Equal(t, []string{"closed->open"}, recorder.get())
+
+		called := false
+		err := b.Do(func() error {
+			called = true
+			return nil
+		})
+		assert.False(t, called)
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, Unavailable, KindOf(err))
+		delay, ok := RetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, defaultBreakerOpenTimeout, delay)
+	})
+
+	t.Run("Breaker returns the error of the call", func(t *testing.T) {
+		b := NewBreaker(BreakerOptions{Clock: newFakeClock()})
+
+		require.ErrorIs(t, b.Do(func() error { return errBackend }), errBackend)
+		require.NoError(t, b.Do(func() error { return nil }))
+	})
+
+	t.Run("Breaker counts failures within the window", func(t *testing.T) {
+		clock := newFakeClock()
+		b := NewBreaker(BreakerOptions{FailureThreshold: 3, Window: time.Minute, Clock: clock})
+
+		failN(b, 2, errBackend)
+		clock.Advance(time.Minute)
+		failN(b, 2, errBackend)
+		assert.Equal(t, BreakerClosed, b.State())
+
+		clock.Advance(30 * time.Second)
+		failN(b, 1, errBackend)
+		assert.Equal(t, BreakerOpen, b.State())
+	})
+
+	t.Run("Breaker ignores errors handled with a nil result", func(t *testing.T) {
+		b := NewBreaker(BreakerOptions{
+			FailureThreshold: 1,
+			Clock:            newFakeClock(),
+			Matchers: []ErrorMatcher{
+				OnCanceled(func(error) error { return nil }),
+				OnType(func(*ValidationError) error { return nil }),
+				OnSentinel(errBackend, func(err error) error { return err }),
+			},
+		})
+
+		failN(b, 3, context.Canceled)
+		failN(b, 3, fmt.Errorf("validate: %w", &ValidationError{Field: "name"}))
+		assert.Equal(t, BreakerClosed, b.State())
+
+		failN(b, 1, errBackend)
+		assert.Equal(t, BreakerOpen, b.State())
+	})
+
+	t.Run("Breaker closes after successful probes", func(t *testing.T) {
+		clock := newFakeClock()
+		recorder := &stateRecorder{}
+		b := NewBreaker(BreakerOptions{
+			FailureThreshold: 1,
+			OpenTimeout:      10 * time.Second,
+			HalfOpenProbes:   2,
+			Clock:            clock,
+			OnStateChange:    recorder.record,
+		})
+
+		failN(b, 1, errBackend)
+		clock.Advance(5 * time.Second)
+		err := b.Do(func() error { return nil })
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		delay, _ := RetryAfter(err)
+		assert.Equal(t, 5*time.Second, delay)
+
+		clock.Advance(5 * time.Second)
+		assert.Equal(t, BreakerHalfOpen, b.State())
+
+		require.NoError(t, b.Do(func() error { return nil }))
+		assert.Equal(t, BreakerHalfOpen, b.State())
+		require.NoError(t, b.Do(func() error { return nil }))
+		assert.Equal(t, BreakerClosed, b.State())
+
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, recorder.get())
+	})
+
+	t.Run("Breaker reopens after a failed probe", func(t *testing.T) {
+		clock := newFakeClock()
+		recorder := &stateRecorder{}
+		b := NewBreaker(BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second, Clock: clock, OnStateChange: recorder.record})
+
+		failN(b, 1, errBackend)
+		clock.Advance(time.Second)
+		failN(b, 1, errBackend)
+		assert.Equal(t, BreakerOpen, b.State())
+
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open"}, recorder.get())
+	})
+
+	t.Run("Breaker ignored errors do not decide probes", func(t *testing.T) {
+		clock := newFakeClock()
+		recorder := &stateRecorder{}
+		b := NewBreaker(BreakerOptions{
+			FailureThreshold: 1,
+			OpenTimeout:      time.Second,
+			Clock:            clock,
+			OnStateChange:    recorder.record,
+			Matchers: []ErrorMatcher{
+				OnCanceled(func(error) error { return nil }),
+			},
+		})
+
+		failN(b, 1, errBackend)
+		clock.Advance(time.Second)
+		failN(b, 1, context.Canceled)
+		assert.Equal(t, BreakerHalfOpen, b.State())
+
+		require.NoError(t, b.Do(func() error { return nil }))
+		assert.Equal(t, BreakerClosed, b.State())
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, recorder.get())
+	})
+
+	t.Run("Breaker limits probes in flight", func(t *testing.T) {
+		clock := newFakeClock()
+		b := NewBreaker(BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second, Clock: clock})
+
+		failN(b, 1, errBackend)
+		clock.Advance(time.Second)
+
+		var nested error
+		err := b.Do(func() error {
+			nested = b.Do(func() error { return nil })
+			return nil
+		})
+		require.NoError(t, err)
+		require.ErrorIs(t, nested, ErrCircuitOpen)
+		assert.Equal(t, BreakerClosed, b.State())
+	})
+
+	t.Run("Breaker ignores outcomes from an earlier state", func(t *testing.T) {
+		clock := newFakeClock()
+		b := NewBreaker(BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Second, Clock: clock})
+
+		err := b.Do(func() error {
+			failN(b, 1, errBackend)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, BreakerOpen, b.State())
+	})
+
+	t.Run("Breaker records a panic as a failure", func(t *testing.T) {
+		b := NewBreaker(BreakerOptions{FailureThreshold: 1, Clock: newFakeClock()})
+
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = b.Do(func() error { panic("boom") })
+		})
+		assert.Equal(t, BreakerOpen, b.State())
+	})
+
+	t.Run("Breaker allows the callback to call the breaker", func(t *testing.T) {
+		var states []BreakerState
+		var b *Breaker
+		b = NewBreaker(BreakerOptions{
+			FailureThreshold: 1,
+			Clock:            newFakeClock(),
+			OnStateChange: func(_, _ BreakerState) {
+				states = append(states, b.State())
+			},
+		})
+
+		failN(b, 1, errBackend)
+		assert.Equal(t, []BreakerState{BreakerOpen}, states)
+	})
+
+	t.Run("Breaker is safe for concurrent use", func(t *testing.T) {
+		clock := newFakeClock()
+		b := NewBreaker(BreakerOptions{FailureThreshold: 50, OpenTimeout: time.Second, Clock: clock})
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() {
+				for j := range 100 {
+					_ = b.Do(func() error {
+						if (i+j)%2 == 0 {
+							return errBackend
+						}
+						return nil
+					})
+					if j%10 == 0 {
+						clock.Advance(100 * time.Millisecond)
+					}
+				}
+			})
+		}
+		wg.Wait()
+		_ = b.State()
+	})
+}
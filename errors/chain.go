@@ -0,0 +1,112 @@
+package errors
+
+// asType finds the first error in err's tree that has type T and returns it.
+// It follows the same rules as errors.As, including custom As methods, but is
+// resolved at compile time through the type parameter, so it needs neither
+// reflection nor a heap-allocated target for the common cases.
+func asType[T error](err error) (T, bool) {
+	var zero T
+	for err != nil {
+		if typedErr, ok := err.(T); ok {
+			return typedErr, true
+		}
+		if x, ok := err.(interface{ As(target any) bool }); ok {
+			var typedErr T
+			if x.As(&typedErr) {
+				return typedErr, true
+			}
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				if typedErr, ok := asType[T](e); ok {
+					return typedErr, true
+				}
+			}
+			return zero, false
+		default:
+			return zero, false
+		}
+	}
+	return zero, false
+}
+
+// leaves splits err into the independent errors of its tree.
+// Errors implementing Unwrap() []error with several children are split into
+// those children, and single wrappers are looked through to find such errors.
+// An error whose chain contains no such multi-error is a leaf on its own and is
+// returned with its wrappers intact.
+func leaves(err error) []error {
+	var result []error
+	var visit func(error)
+	visit = func(e error) {
+		children := split(e)
+		if len(children) < 2 {
+			result = append(result, e)
+			return
+		}
+		for _, child := range children {
+			visit(child)
+		}
+	}
+
+	if err != nil {
+		visit(err)
+	}
+	return result
+}
+
+// split returns the non-nil children of the first error in err's chain that
+// joins several errors. Single wrappers and multi-errors with a single child
+// are looked through. It returns nil if the chain joins no errors.
+func split(err error) []error {
+	for err != nil {
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			var children []error
+			for _, child := range x.Unwrap() {
+				if child != nil {
+					children = append(children, child)
+				}
+			}
+			if len(children) != 1 {
+				return children
+			}
+			err = children[0]
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every error in err's tree in depth-first pre-order,
+// starting with err itself. Single wrappers are followed through Unwrap() error
+// and joined errors through Unwrap() []error. It stops early if fn returns false.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, child := range x.Unwrap() {
+				if !walk(child, fn) {
+					return false
+				}
+			}
+			return true
+		default:
+			return true
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asTargetError converts itself into a *ValidationError through a custom As method.
+type asTargetError struct{}
+
+func (asTargetError) Error() string { return "as target" }
+
+func (asTargetError) As(target any) bool {
+	if ve, ok := target.(**ValidationError); ok {
+		*ve = &ValidationError{Field: "converted"}
+		return true
+	}
+	return false
+}
+
+func TestAsType(t *testing.T) {
+	t.Run("asType with nil error", func(t *testing.T) {
+		typedErr, ok := asType[*CustomError](nil)
+		assert.False(t, ok)
+		assert.Nil(t, typedErr)
+	})
+
+	t.Run("asType with direct match", func(t *testing.T) {
+		original := &CustomError{Code: 400}
+		typedErr, ok := asType[*CustomError](original)
+		assert.True(t, ok)
+		assert.Same(t, original, typedErr)
+	})
+
+	t.Run("asType with wrapped error", func(t *testing.T) {
+		original := &CustomError{Code: 500}
+		wrappedErr := fmt.Errorf("layer2: %w", fmt.Errorf("layer1: %w", original))
+		typedErr, ok := asType[*CustomError](wrappedErr)
+		assert.True(t, ok)
+		assert.Same(t, original, typedErr)
+	})
+
+	t.Run("asType with joined error", func(t *testing.T) {
+		original := &ValidationError{Field: "email"}
+		joinedErr := errors.Join(io.EOF, fmt.Errorf("wrapped: %w", original))
+		typedErr, ok := asType[*ValidationError](joinedErr)
+		assert.True(t, ok)
+		assert.Same(t, original, typedErr)
+	})
+
+	t.Run("asType with no match", func(t *testing.T) {
+		joinedErr := errors.Join(io.EOF, fmt.Errorf("wrapped: %w", io.ErrClosedPipe))
+		_, ok := asType[*ValidationError](joinedErr)
+		assert.False(t, ok)
+	})
+
+	t.Run("asType distinguishes value and pointer types", func(t *testing.T) {
+		_, ok := asType[*CustomError](CustomError{Code: 1})
+		assert.False(t, ok)
+
+		typedErr, ok := asType[CustomError](CustomError{Code: 1})
+		assert.True(t, ok)
+		assert.Equal(t, 1, typedErr.Code)
+	})
+
+	t.Run("asType with interface type", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("wrapped: %w", &CustomError{Code: 2})
+		typedErr, ok := asType[interface{ Error() string }](wrappedErr)
+		assert.True(t, ok)
+		assert.Equal(t, wrappedErr, typedErr)
+	})
+
+	t.Run("asType honors custom As method", func(t *testing.T) {
+		typedErr, ok := asType[*ValidationError](fmt.Errorf("wrapped: %w", asTargetError{}))
+		assert.True(t, ok)
+		require.NotNil(t, typedErr)
+		assert.Equal(t, "converted", typedErr.Field)
+	})
+
+	t.Run("asType agrees with errors.As", func(t *testing.T) {
+		errs := []error{
+			io.EOF,
+			&CustomError{Code: 1},
+			fmt.Errorf("wrapped: %w", &ValidationError{}),
+			errors.Join(io.EOF, &CustomError{Code: 2}),
+			asTargetError{},
+		}
+		for _, err := range errs {
+			var target *ValidationError
+			_, ok := asType[*ValidationError](err)
+			assert.Equal(t, errors.As(err, &target), ok, "error: %v", err)
+		}
+	})
+}
+
+func TestLeaves(t *testing.T) {
+	t.Run("leaves of nil error", func(t *testing.T) {
+		assert.Empty(t, leaves(nil))
+	})
+
+	t.Run("leaves of single error", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("wrapped: %w", io.EOF)
+		assert.Equal(t, []error{wrappedErr}, leaves(wrappedErr))
+	})
+
+	t.Run("leaves of joined errors", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("wrapped: %w", io.EOF)
+		joinedErr := errors.Join(ErrSentinel1, wrappedErr, ErrSentinel2)
+		assert.Equal(t, []error{ErrSentinel1, wrappedErr, ErrSentinel2}, leaves(joinedErr))
+	})
+
+	t.Run("leaves of nested joined errors", func(t *testing.T) {
+		joinedErr := errors.Join(ErrSentinel1, errors.Join(io.EOF, io.ErrClosedPipe))
+		assert.Equal(t, []error{ErrSentinel1, io.EOF, io.ErrClosedPipe}, leaves(joinedErr))
+	})
+
+	t.Run("leaves looks through wrapped joined errors", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("batch: %w", errors.Join(ErrSentinel1, ErrSentinel2))
+		assert.Equal(t, []error{ErrSentinel1, ErrSentinel2}, leaves(wrappedErr))
+	})
+
+	t.Run("leaves of single-element joins", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("wrapped: %w", errors.Join(ErrSentinel1))
+		assert.Equal(t, []error{wrappedErr}, leaves(wrappedErr))
+		innerJoin := errors.Join(ErrSentinel2)
+		assert.Equal(t, []error{ErrSentinel1, innerJoin}, leaves(errors.Join(ErrSentinel1, innerJoin)))
+	})
+
+	t.Run("leaves of multi-wrap fmt.Errorf", func(t *testing.T) {
+		multiErr := fmt.Errorf("%w and %w", io.EOF, ErrSentinel1)
+		assert.Equal(t, []error{io.EOF, ErrSentinel1}, leaves(multiErr))
+	})
+}
+
+func TestWalk(t *testing.T) {
+	t.Run("walk visits tree in pre-order", func(t *testing.T) {
+		inner := fmt.Errorf("inner: %w", io.EOF)
+		joined := errors.Join(inner, ErrSentinel1)
+		outer := fmt.Errorf("outer: %w", joined)
+
+		var visited []error
+		completed := walk(outer, func(e error) bool {
+			visited = append(visited, e)
+			return true
+		})
+
+		assert.True(t, completed)
+		assert.Equal(t, []error{outer, joined, inner, io.EOF, ErrSentinel1}, visited)
+	})
+
+	t.Run("walk stops early", func(t *testing.T) {
+		joined := errors.Join(io.EOF, ErrSentinel1, ErrSentinel2)
+
+		var visited []error
+		completed := walk(joined, func(e error) bool {
+			visited = append(visited, e)
+			return e != ErrSentinel1
+		})
+
+		assert.False(t, completed)
+		assert.Equal(t, []error{joined, io.EOF, ErrSentinel1}, visited)
+	})
+
+	t.Run("walk with nil error", func(t *testing.T) {
+		called := false
+		assert.True(t, walk(nil, func(error) bool {
+			called = true
+			return true
+		}))
+		assert.False(t, called)
+	})
+}
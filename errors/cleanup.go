@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"sync"
+)
+
+// Close closes closer and joins its error, if any, into *errp. It is meant to
+// be deferred in functions with a named error result, so that close errors are
+// neither dropped nor allowed to hide the function's own error.
+//
+// The joined error matches both errors with errors.Is and errors.As, so it can
+// be handled as a whole with Handle or HandleOr, or error by error with HandleAll.
+//
+// Example:
+//
+//	func writeReport(path string, report []byte) (err error) {
+//	    f, err := os.Create(path)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    defer errors.Close(&err, f)
+//
+//	    _, err = f.Write(report)
+//	    return err
+//	}
+//
+//	err := errors.HandleErrorOrDefault(writeReport(path, report),
+//	    func(e error) error { return fmt.Errorf("write report: %w", e) },
+//	    errors.OnSentinel(fs.ErrPermission, func(e error) error { return ErrReadOnly }),
+//	)
+func Close(errp *error, closer io.Closer) {
+	Defer(errp, closer.Close)
+}
+
+// Defer calls fn and joins its error, if any, into *errp. It behaves like Close
+// for cleanup steps that are not an io.Closer.
+//
+// Example:
+//
+//	func migrate(ctx context.Context, db *sql.DB) (err error) {
+//	    tx, err := db.BeginTx(ctx, nil)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    defer errors.Defer(&err, func() error {
+//	        if err != nil {
+//	            return tx.Rollback()
+//	        }
+//	        return tx.Commit()
+//	    })
+//	    ...
+//	}
+func Defer(errp *error, fn func() error) {
+	joinInto(errp, fn())
+}
+
+// joinInto joins err into *errp, keeping *errp as is if err is nil and using err
+// alone if *errp is nil.
+func joinInto(errp *error, err error) {
+	switch {
+	case err == nil:
+	case *errp == nil:
+		*errp = err
+	default:
+		*errp = errors.Join(*errp, err)
+	}
+}
+
+// Closers is a stack of cleanup functions that are run in reverse order of
+// registration, like deferred calls, when Close is called. It is useful when
+// resources are acquired in a constructor and released together later.
+//
+// The zero value is an empty stack. Closers is safe for concurrent use.
+type Closers struct {
+	mu  sync.Mutex
+	fns []func() error
+}
+
+// Add pushes closer onto the stack.
+func (c *Closers) Add(closer io.Closer) {
+	c.AddFunc(closer.Close)
+}
+
+// AddFunc pushes fn onto the stack.
+func (c *Closers) AddFunc(fn func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fns = append(c.fns, fn)
+}
+
+// Close runs every function on the stack, from the last added to the first,
+// and empties the stack. It runs all of them even if some fail, and returns
+// their errors joined with errors.Join, in the order they occurred.
+// Closers implements io.Closer, so it can itself be added to another Closers
+// or passed to Close.
+//
+// Example:
+//
+//	func NewServer(cfg Config) (_ *Server, err error) {
+//	    var closers errors.Closers
+//	    defer func() {
+//	        if err != nil {
+//	            errors.Close(&err, &closers) // release what was acquired so far
+//	        }
+//	    }()
+//
+//	    db, err := sql.Open("pgx", cfg.DSN)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    closers.Add(db)
+//
+//	    ln, err := net.Listen("tcp", cfg.Addr)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    closers.Add(ln)
+//
+//	    return &Server{db: db, ln: ln, closers: &closers}, nil
+//	}
+//
+//	func (s *Server) Close() error {
+//	    return s.closers.Close()
+//	}
+func (c *Closers) Close() error {
+	c.mu.Lock()
+	fns := c.fns
+	c.fns = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for _, fn := range slices.Backward(fns) {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,178 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCloser is an io.Closer that records its calls.
+type testCloser struct {
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (c testCloser) Close() error {
+	*c.calls = append(*c.calls, c.name)
+	return c.err
+}
+
+func TestClose(t *testing.T) {
+	errClose := errors.New("close failed")
+
+	run := func(bodyErr, closeErr error) error {
+		var calls []string
+		return func() (err error) {
+			defer Close(&err, testCloser{name: "f", err: closeErr, calls: &calls})
+			return bodyErr
+		}()
+	}
+
+	t.Run("Close without errors", func(t *testing.T) {
+		assert.NoError(t, run(nil, nil))
+	})
+
+	t.Run("Close with close error only", func(t *testing.T) {
+		err := run(nil, errClose)
+		assert.Same(t, errClose, err)
+	})
+
+	t.Run("Close with body error only", func(t *testing.T) {
+		err := run(io.EOF, nil)
+		assert.Same(t, io.EOF, err)
+	})
+
+	t.Run("Close with both errors", func(t *testing.T) {
+		err := run(io.EOF, errClose)
+		require.EqualError(t, err, "EOF\nclose failed")
+		require.ErrorIs(t, err, io.EOF)
+		require.ErrorIs(t, err, errClose)
+	})
+
+	t.Run("Close with HandleErrorOrDefault", func(t *testing.T) {
+		err := HandleErrorOrDefault(run(io.EOF, errClose),
+			func(e error) error { return fmt.Errorf("unexpected: %w", e) },
+			OnSentinel(ErrSentinel1, func(e error) error { return nil }),
+		)
+		require.ErrorIs(t, err, errClose)
+
+		result := HandleErrorOrDefault(run(nil, errClose), nil,
+			OnSentinel(errClose, func(e error) error { return errors.New("handled close") }),
+		)
+		require.EqualError(t, result, "handled close")
+	})
+}
+
+func TestDefer(t *testing.T) {
+	t.Run("Defer sees the function result", func(t *testing.T) {
+		var rolledBack bool
+		err := func() (err error) {
+			defer Defer(&err, func() error {
+				rolledBack = err != nil
+				return nil
+			})
+			return io.EOF
+		}()
+		require.ErrorIs(t, err, io.EOF)
+		assert.True(t, rolledBack)
+	})
+
+	t.Run("Defer joins the cleanup error", func(t *testing.T) {
+		err := func() (err error) {
+			defer Defer(&err, func() error { return io.ErrClosedPipe })
+			return io.EOF
+		}()
+		require.ErrorIs(t, err, io.EOF)
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+}
+
+func TestClosers(t *testing.T) {
+	t.Run("Closers runs in LIFO order", func(t *testing.T) {
+		var (
+			calls   []string
+			closers Closers
+		)
+		closers.Add(testCloser{name: "db", calls: &calls})
+		closers.AddFunc(func() error {
+			calls = append(calls, "listener")
+			return nil
+		})
+		closers.Add(testCloser{name: "cache", calls: &calls})
+
+		require.NoError(t, closers.Close())
+		assert.Equal(t, []string{"cache", "listener", "db"}, calls)
+	})
+
+	t.Run("Closers joins every failure", func(t *testing.T) {
+		var (
+			calls   []string
+			closers Closers
+		)
+		closers.Add(testCloser{name: "a", err: io.EOF, calls: &calls})
+		closers.Add(testCloser{name: "b", calls: &calls})
+		closers.Add(testCloser{name: "c", err: io.ErrClosedPipe, calls: &calls})
+
+		err := closers.Close()
+		require.EqualError(t, err, "io: read/write on closed pipe\nEOF")
+		assert.Equal(t, []string{"c", "b", "a"}, calls)
+
+		unhandled, _ := HandleAll(err, OnSentinel(io.EOF, func(e error) error { return nil }))
+		assert.Equal(t, []error{io.ErrClosedPipe}, unhandled)
+	})
+
+	t.Run("Closers empties the stack", func(t *testing.T) {
+		var (
+			calls   []string
+			closers Closers
+		)
+		closers.Add(testCloser{name: "a", calls: &calls})
+
+		require.NoError(t, closers.Close())
+		require.NoError(t, closers.Close())
+		assert.Equal(t, []string{"a"}, calls)
+	})
+
+	t.Run("Closers nested in Close", func(t *testing.T) {
+		var (
+			calls   []string
+			closers Closers
+		)
+		closers.Add(testCloser{name: "a", err: io.EOF, calls: &calls})
+
+		err := func() (err error) {
+			defer Close(&err, &closers)
+			return nil
+		}()
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("Closers concurrent Add", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			count   int
+			closers Closers
+			wg      sync.WaitGroup
+		)
+		for range 50 {
+			wg.Go(func() {
+				closers.AddFunc(func() error {
+					mu.Lock()
+					count++
+					mu.Unlock()
+					return nil
+				})
+			})
+		}
+		wg.Wait()
+
+		require.NoError(t, closers.Close())
+		assert.Equal(t, 50, count)
+	})
+}
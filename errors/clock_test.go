@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a Clock whose time only moves when a timer is waited on
+// or when it is advanced explicitly.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After advances the clock by d and returns a channel that is already ready.
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+func TestClockOrSystem(t *testing.T) {
+	t.Run("nil returns system clock", func(t *testing.T) {
+		clock := clockOrSystem(nil)
+		assert.IsType(t, systemClock{}, clock)
+		assert.WithinDuration(t, time.Now(), clock.Now(), time.Second)
+
+		select {
+		case <-clock.After(time.Millisecond):
+		case <-time.After(time.Second):
+			t.Fatal("system clock timer did not fire")
+		}
+	})
+
+	t.Run("custom clock is kept", func(t *testing.T) {
+		clock := newFakeClock()
+		assert.Same(t, clock, clockOrSystem(clock))
+	})
+}
@@ -0,0 +1,241 @@
+package errors
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Handler is a reusable, compiled set of matchers. It is built once, typically
+// in a package-level variable, and then used at every call site, so the matcher
+// slice is not rebuilt and allocated on each call as it is with Handle.
+//
+// Matchers are tried in order of decreasing Priority, and matchers with equal
+// priorities keep the order in which they were added. A Handler may also have
+// a default handler for errors no matcher matches, and middleware applied to
+// every handler it runs.
+//
+// A Handler is immutable: NewHandler, Use, WithDefault, Extend and Override all
+// return a new Handler and leave the one they are called on unchanged. It is
+// therefore safe for concurrent use, and a company-wide base Handler can be
+// shared and specialized by each service.
+type Handler struct {
+	matchers   []ErrorMatcher
+	middleware []Middleware
+	dft        ErrorHandler
+
+	// The matchers and default handler with the middleware applied.
+	wrapped    []ErrorMatcher
+	wrappedDft ErrorHandler
+}
+
+// NewHandler returns a Handler that handles errors with the given matchers.
+// It panics if two matchers have the same non-empty name.
+//
+// Example:
+//
+//	var handler = errors.NewHandler(
+//	    errors.OnSentinel(ErrNotFound, func(e error) error {
+//	        return ErrUserMissing
+//	    }).WithName("not_found"),
+//	    errors.OnType(func(e *ValidationError) error {
+//	        return fmt.Errorf("invalid input: %w", e)
+//	    }).WithName("validation").WithPriority(10),
+//	).WithDefault(func(e error) error {
+//	    return fmt.Errorf("unexpected: %w", e)
+//	}).Use(errors.LogHandled(logger, slog.LevelWarn))
+//
+//	err = handler.MustHandle(err)
+func NewHandler(matchers ...ErrorMatcher) *Handler {
+	return compileHandler(slices.Clone(matchers), nil, nil)
+}
+
+// compileHandler checks and orders the matchers, and applies the middleware
+// to the matchers and the default handler.
+func compileHandler(matchers []ErrorMatcher, middleware []Middleware, dft ErrorHandler) *Handler {
+	seen := make(map[string]struct{}, len(matchers))
+	for _, matcher := range matchers {
+		if matcher.Name == "" {
+			continue
+		}
+		if _, dup := seen[matcher.Name]; dup {
+			panic(fmt.Sprintf("errors: duplicate matcher name %q", matcher.Name))
+		}
+		seen[matcher.Name] = struct{}{}
+	}
+	slices.SortStableFunc(matchers, func(a, b ErrorMatcher) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+
+	h := &Handler{
+		matchers:   matchers,
+		middleware: middleware,
+		dft:        dft,
+		wrapped:    matchers,
+		wrappedDft: dft,
+	}
+	if len(middleware) > 0 {
+		h.wrapped = make([]ErrorMatcher, len(matchers))
+		for i, matcher := range matchers {
+			wrapped := matcher
+			wrapped.Handler = h.wrap(matcher)
+			h.wrapped[i] = wrapped
+		}
+		if dft != nil {
+			h.wrappedDft = h.wrap(ErrorMatcher{Handler: dft})
+		}
+	}
+	return h
+}
+
+// wrap returns the matcher's handler wrapped in the Handler's middleware.
+func (h *Handler) wrap(matcher ErrorMatcher) ErrorHandler {
+	handler := matcher.Handler
+	for _, mw := range slices.Backward(h.middleware) {
+		handler = mw(matcher, handler)
+	}
+	return handler
+}
+
+// Use returns a copy of the Handler with additional middleware.
+// Middleware run in the order they are added, so the first one is the outermost
+// and sees the error first.
+//
+// Example:
+//
+//	handler = handler.Use(
+//	    errors.LogHandled(logger, slog.LevelInfo),
+//	    errors.Observe(func(m errors.ErrorMatcher, err, result error) {
+//	        handledErrors.WithLabelValues(m.Name).Inc()
+//	    }),
+//	)
+func (h *Handler) Use(middleware ...Middleware) *Handler {
+	return compileHandler(slices.Clone(h.matchers), slices.Concat(h.middleware, middleware), h.dft)
+}
+
+// WithDefault returns a copy of the Handler that passes errors no matcher
+// matches to dft. A nil dft removes the default handler.
+//
+// Example:
+//
+//	handler = handler.WithDefault(errors.ToPublic(errors.DefaultPublicMessage))
+func (h *Handler) WithDefault(dft ErrorHandler) *Handler {
+	return compileHandler(slices.Clone(h.matchers), h.middleware, dft)
+}
+
+// Extend returns a copy of the Handler with additional matchers. They are
+// ordered by priority together with the existing ones, and come after existing
+// matchers of equal priority. It panics if a new matcher's name is already used.
+//
+// Example:
+//
+//	var billingHandler = baseHandler.Extend(
+//	    errors.OnSentinel(ErrCardDeclined, declined).WithName("card_declined"),
+//	)
+func (h *Handler) Extend(matchers ...ErrorMatcher) *Handler {
+	return compileHandler(slices.Concat(h.matchers, matchers), h.middleware, h.dft)
+}
+
+// Override returns a copy of the Handler in which each given matcher replaces
+// the existing matcher with the same name. The replacement takes the place of
+// the original, and is then ordered by its own priority.
+// It panics if a matcher has no name or its name is not used by the Handler.
+//
+// Example:
+//
+//	var adminHandler = baseHandler.Override(
+//	    errors.OnKind(errors.PermissionDenied, func(e error) error {
+//	        return e // show the details to administrators
+//	    }).WithName("permission_denied"),
+//	)
+func (h *Handler) Override(matchers ...ErrorMatcher) *Handler {
+	result := slices.Clone(h.matchers)
+	for _, matcher := range matchers {
+		i := slices.IndexFunc(result, func(m ErrorMatcher) bool {
+			return matcher.Name != "" && m.Name == matcher.Name
+		})
+		if i < 0 {
+			panic(fmt.Sprintf("errors: Override called with unknown matcher name %q", matcher.Name))
+		}
+		result[i] = matcher
+	}
+	return compileHandler(result, h.middleware, h.dft)
+}
+
+// Matchers returns the Handler's matchers in the order they are tried.
+func (h *Handler) Matchers() []ErrorMatcher {
+	return slices.Clone(h.matchers)
+}
+
+// MatcherFor returns the first matcher that matches err, without running its
+// handler. It reports false if no matcher matches, even if the Handler has a
+// default handler.
+//
+// Example:
+//
+//	if m, ok := handler.MatcherFor(err); ok {
+//	    span.SetAttributes(attribute.String("error.matcher", m.Name))
+//	}
+func (h *Handler) MatcherFor(err error) (ErrorMatcher, bool) {
+	for _, matcher := range h.matchers {
+		if matcher.Match(err) {
+			return matcher, true
+		}
+	}
+	return ErrorMatcher{}, false
+}
+
+// Handle processes err against the Handler's matchers, as the package-level
+// Handle function does, running the matched handler through the middleware.
+// It returns (true, handlerResult) if a matcher matches. Otherwise it returns
+// (true, defaultResult) if the Handler has a default handler, or (false, err)
+// if it has none. If err is nil, returns (true, nil).
+func (h *Handler) Handle(err error) (bool, error) {
+	ok, result := HandleError(err, h.wrapped...)
+	if !ok && h.wrappedDft != nil {
+		return true, h.wrappedDft(err)
+	}
+	return ok, result
+}
+
+// HandleCtx processes err against the Handler's matchers like the package-level
+// HandleCtx function does. If no matcher matches, the Handler's default handler,
+// if any, is used as in Handle.
+func (h *Handler) HandleCtx(ctx context.Context, err error) (bool, error) {
+	if handled, result := handleDone(ctx, err, h.wrapped); handled {
+		return true, result
+	}
+	return h.Handle(err)
+}
+
+// MustHandle processes err against the Handler's matchers, as the package-level
+// MustHandle function does. It panics if no matcher matches and the Handler has
+// no default handler.
+func (h *Handler) MustHandle(err error) error {
+	ok, err := h.Handle(err)
+	if !ok {
+		panic(fmt.Errorf("unhandled error of type %T: %w", err, err))
+	}
+	return err
+}
+
+// HandleOr processes err against the Handler's matchers, as the package-level
+// HandleOr function does. Unmatched errors are passed to dft, which also runs
+// through the middleware. If dft is nil, the Handler's default handler is used,
+// and unmatched errors return nil if there is none either.
+func (h *Handler) HandleOr(err error, dft ErrorHandler) error {
+	if dft == nil {
+		ok, result := h.Handle(err)
+		if !ok {
+			return nil
+		}
+		return result
+	}
+
+	ok, result := HandleError(err, h.wrapped...)
+	if ok {
+		return result
+	}
+	return h.wrap(ErrorMatcher{Handler: dft})(err)
+}
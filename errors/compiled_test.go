@@ -0,0 +1,328 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	errHandled := errors.New("handled")
+	matchers := []ErrorMatcher{
+		OnSentinel(io.EOF, func(e error) error { return errHandled }),
+		OnType(func(e *ValidationError) error { return fmt.Errorf("invalid %s", e.Field) }),
+	}
+
+	t.Run("Handler without middleware", func(t *testing.T) {
+		handler := NewHandler(matchers...)
+
+		handled, result := handler.Handle(fmt.Errorf("read: %w", io.EOF))
+		assert.True(t, handled)
+		require.ErrorIs(t, result, errHandled)
+
+		handled, result = handler.Handle(io.ErrUnexpectedEOF)
+		assert.False(t, handled)
+		require.ErrorIs(t, result, io.ErrUnexpectedEOF)
+
+		handled, result = handler.Handle(nil)
+		assert.True(t, handled)
+		assert.NoError(t, result)
+	})
+
+	t.Run("Handler with middleware order", func(t *testing.T) {
+		var calls []string
+		handler := NewHandler(matchers...).Use(
+			recordingMiddleware("first", &calls),
+			recordingMiddleware("second", &calls),
+		)
+
+		handled, result := handler.Handle(&ValidationError{Field: "name"})
+		assert.True(t, handled)
+		require.EqualError(t, result, "invalid name")
+		assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, calls)
+	})
+
+	t.Run("Handler middleware skipped for unmatched errors", func(t *testing.T) {
+		var calls []string
+		handler := NewHandler(matchers...).Use(recordingMiddleware("mw", &calls))
+
+		handled, _ := handler.Handle(io.ErrUnexpectedEOF)
+		assert.False(t, handled)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("Handler Use leaves the original unchanged", func(t *testing.T) {
+		var calls []string
+		base := NewHandler(matchers...)
+		withFirst := base.Use(recordingMiddleware("first", &calls))
+		withBoth := withFirst.Use(recordingMiddleware("second", &calls))
+
+		_, _ = base.Handle(io.EOF)
+		assert.Empty(t, calls)
+
+		_, _ = withFirst.Handle(io.EOF)
+		assert.Equal(t, []string{"first before", "first after"}, calls)
+
+		calls = nil
+		_, _ = withBoth.Handle(io.EOF)
+		assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, calls)
+	})
+
+	t.Run("Handler middleware receives the matcher", func(t *testing.T) {
+		var matched []bool
+		handler := NewHandler(matchers...).Use(func(m ErrorMatcher, next ErrorHandler) ErrorHandler {
+			return func(err error) error {
+				matched = append(matched, m.Match(&ValidationError{}))
+				return next(err)
+			}
+		})
+
+		_, _ = handler.Handle(io.EOF)
+		_, _ = handler.Handle(&ValidationError{})
+		assert.Equal(t, []bool{false, true}, matched)
+	})
+
+	t.Run("Handler middleware can replace the result", func(t *testing.T) {
+		handler := NewHandler(matchers...).Use(func(_ ErrorMatcher, next ErrorHandler) ErrorHandler {
+			return func(err error) error {
+				return Wrap(next(err), "observed")
+			}
+		})
+
+		_, result := handler.Handle(io.EOF)
+		require.EqualError(t, result, "observed: handled")
+	})
+
+	t.Run("Handler MustHandle", func(t *testing.T) {
+		handler := NewHandler(matchers...)
+
+		require.ErrorIs(t, handler.MustHandle(io.EOF), errHandled)
+		assert.PanicsWithError(t, "unhandled error of type *errors.errorString: unexpected EOF", func() {
+			_ = handler.MustHandle(io.ErrUnexpectedEOF)
+		})
+	})
+
+	t.Run("Handler HandleOr", func(t *testing.T) {
+		var calls []string
+		handler := NewHandler(matchers...).Use(recordingMiddleware("mw", &calls))
+
+		result := handler.HandleOr(io.ErrUnexpectedEOF, func(e error) error {
+			return fmt.Errorf("default: %w", e)
+		})
+		require.EqualError(t, result, "default: unexpected EOF")
+		assert.Equal(t, []string{"mw before", "mw after"}, calls)
+
+		require.NoError(t, handler.HandleOr(io.ErrUnexpectedEOF, nil))
+		require.ErrorIs(t, handler.HandleOr(io.EOF, nil), errHandled)
+	})
+
+	t.Run("Handler concurrent use", func(t *testing.T) {
+		var (
+			mu    sync.Mutex
+			count int
+		)
+		handler := NewHandler(matchers...).Use(Observe(func(ErrorMatcher, error, error) {
+			mu.Lock()
+			count++
+			mu.Unlock()
+		}))
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Go(func() {
+				_, _ = handler.Handle(io.EOF)
+			})
+		}
+		wg.Wait()
+		assert.Equal(t, 50, count)
+	})
+}
+
+func TestHandlerOrdering(t *testing.T) {
+	record := func(name string, calls *[]string) ErrorMatcher {
+		return On(func(error) bool { return true }, func(e error) error {
+			*calls = append(*calls, name)
+			return nil
+		}).WithName(name)
+	}
+	names := func(h *Handler) []string {
+		var result []string
+		for _, m := range h.Matchers() {
+			result = append(result, m.Name)
+		}
+		return result
+	}
+
+	t.Run("Handler orders matchers by priority", func(t *testing.T) {
+		var calls []string
+		handler := NewHandler(
+			record("low", &calls).WithPriority(-1),
+			record("first", &calls),
+			record("high", &calls).WithPriority(5),
+			record("second", &calls),
+		)
+		assert.Equal(t, []string{"high", "first", "second", "low"}, names(handler))
+
+		_, _ = handler.Handle(io.EOF)
+		assert.Equal(t, []string{"high"}, calls)
+	})
+
+	t.Run("Handler with duplicate names", func(t *testing.T) {
+		var calls []string
+		assert.PanicsWithValue(t, `errors: duplicate matcher name "a"`, func() {
+			NewHandler(record("a", &calls), record("a", &calls))
+		})
+		assert.NotPanics(t, func() {
+			NewHandler(OnSentinel(io.EOF, nil), OnSentinel(io.EOF, nil))
+		})
+	})
+
+	t.Run("Handler Extend", func(t *testing.T) {
+		var calls []string
+		base := NewHandler(record("a", &calls), record("b", &calls).WithPriority(1))
+		extended := base.Extend(record("c", &calls), record("d", &calls).WithPriority(2))
+
+		assert.Equal(t, []string{"b", "a"}, names(base))
+		assert.Equal(t, []string{"d", "b", "a", "c"}, names(extended))
+		assert.PanicsWithValue(t, `errors: duplicate matcher name "a"`, func() {
+			base.Extend(record("a", &calls))
+		})
+	})
+
+	t.Run("Handler Override", func(t *testing.T) {
+		var calls []string
+		base := NewHandler(record("a", &calls), record("b", &calls), record("c", &calls))
+		overridden := base.Override(record("b", &calls).WithPriority(1))
+
+		assert.Equal(t, []string{"a", "b", "c"}, names(base))
+		assert.Equal(t, []string{"b", "a", "c"}, names(overridden))
+
+		_, _ = overridden.Handle(io.EOF)
+		assert.Equal(t, []string{"b"}, calls)
+	})
+
+	t.Run("Handler Override replaces the handler in place", func(t *testing.T) {
+		base := NewHandler(
+			OnSentinel(io.EOF, func(e error) error { return errors.New("base") }).WithName("eof"),
+			On(func(error) bool { return true }, func(e error) error { return errors.New("catch-all") }),
+		)
+		overridden := base.Override(
+			OnSentinel(io.EOF, func(e error) error { return errors.New("service") }).WithName("eof"),
+		)
+
+		require.EqualError(t, base.MustHandle(io.EOF), "base")
+		require.EqualError(t, overridden.MustHandle(io.EOF), "service")
+		require.EqualError(t, overridden.MustHandle(io.ErrUnexpectedEOF), "catch-all")
+	})
+
+	t.Run("Handler Override with unknown name", func(t *testing.T) {
+		var calls []string
+		base := NewHandler(record("a", &calls))
+		assert.PanicsWithValue(t, `errors: Override called with unknown matcher name "z"`, func() {
+			base.Override(record("z", &calls))
+		})
+		assert.PanicsWithValue(t, `errors: Override called with unknown matcher name ""`, func() {
+			base.Override(OnSentinel(io.EOF, nil))
+		})
+	})
+
+	t.Run("Handler keeps middleware across derivations", func(t *testing.T) {
+		var calls, mw []string
+		handler := NewHandler(record("a", &calls)).
+			Use(recordingMiddleware("mw", &mw)).
+			Extend(record("b", &calls).WithPriority(1))
+
+		_, _ = handler.Handle(io.EOF)
+		assert.Equal(t, []string{"b"}, calls)
+		assert.Equal(t, []string{"mw before", "mw after"}, mw)
+	})
+}
+
+func TestHandlerDefault(t *testing.T) {
+	handler := NewHandler(
+		OnSentinel(io.EOF, func(e error) error { return nil }),
+	).WithDefault(func(e error) error {
+		return fmt.Errorf("unexpected: %w", e)
+	})
+
+	t.Run("Handler default with matched error", func(t *testing.T) {
+		handled, result := handler.Handle(io.EOF)
+		assert.True(t, handled)
+		assert.NoError(t, result)
+	})
+
+	t.Run("Handler default with unmatched error", func(t *testing.T) {
+		handled, result := handler.Handle(io.ErrUnexpectedEOF)
+		assert.True(t, handled)
+		require.EqualError(t, result, "unexpected: unexpected EOF")
+
+		require.EqualError(t, handler.MustHandle(io.ErrUnexpectedEOF), "unexpected: unexpected EOF")
+		require.EqualError(t, handler.HandleOr(io.ErrUnexpectedEOF, nil), "unexpected: unexpected EOF")
+	})
+
+	t.Run("Handler default overridden by HandleOr", func(t *testing.T) {
+		result := handler.HandleOr(io.ErrUnexpectedEOF, func(e error) error {
+			return errors.New("explicit")
+		})
+		require.EqualError(t, result, "explicit")
+	})
+
+	t.Run("Handler default runs through middleware", func(t *testing.T) {
+		var calls []string
+		_, _ = handler.Use(recordingMiddleware("mw", &calls)).Handle(io.ErrUnexpectedEOF)
+		assert.Equal(t, []string{"mw before", "mw after"}, calls)
+	})
+
+	t.Run("Handler default removed", func(t *testing.T) {
+		handled, result := handler.WithDefault(nil).Handle(io.ErrUnexpectedEOF)
+		assert.False(t, handled)
+		require.ErrorIs(t, result, io.ErrUnexpectedEOF)
+	})
+}
+
+func TestHandlerMatcherFor(t *testing.T) {
+	handler := NewHandler(
+		OnSentinel(io.EOF, nil).WithName("eof"),
+		OnType(func(e *ValidationError) error { return nil }).WithName("validation"),
+	).WithDefault(func(e error) error { return nil })
+
+	m, ok := handler.MatcherFor(fmt.Errorf("wrapped: %w", &ValidationError{}))
+	assert.True(t, ok)
+	assert.Equal(t, "validation", m.Name)
+
+	_, ok = handler.MatcherFor(io.ErrUnexpectedEOF)
+	assert.False(t, ok)
+}
+
+func TestHandlerAllocations(t *testing.T) {
+	handler := NewHandler(
+		OnSentinel(io.ErrUnexpectedEOF, func(e error) error { return e }),
+		OnType(func(e *ValidationError) error { return e }),
+		OnSentinel(io.EOF, func(e error) error { return e }),
+	)
+	err := error(&ValidationError{})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = handler.Handle(err)
+		_, _ = handler.Handle(io.EOF)
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkHandler(b *testing.B) {
+	handler := NewHandler(
+		OnSentinel(io.ErrUnexpectedEOF, func(e error) error { return e }),
+		OnType(func(e *ValidationError) error { return e }),
+		OnSentinel(io.EOF, func(e error) error { return e }),
+	)
+	err := fmt.Errorf("wrapped: %w", io.EOF)
+
+	for b.Loop() {
+		_, _ = handler.Handle(err)
+	}
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Condition reports whether an error satisfies some criterion.
+// Conditions are the building blocks of ErrorMatcher and can be combined with
+// And, Or and Not. Any func(error) bool can be used as a Condition.
+type Condition func(error) bool
+
+// IsAny returns a Condition that matches errors equal to any of the targets.
+// Each target is compared using errors.Is, so wrapped errors match as well.
+//
+// Example:
+//
+//	cond := IsAny(context.Canceled, context.DeadlineExceeded)
+func IsAny(targets ...error) Condition {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasType returns a Condition that matches errors whose chain contains
+// an error of type T, following the same rules as errors.As.
+//
+// Example:
+//
+//	cond := HasType[*ValidationError]()
+func HasType[T error]() Condition {
+	return func(err error) bool {
+		_, ok := asType[T](err)
+		return ok
+	}
+}
+
+// HasMessage returns a Condition that matches errors whose message contains substr.
+// This is intended for third-party errors that expose neither a sentinel value
+// nor a dedicated type.
+//
+// Example:
+//
+//	cond := HasMessage("connection reset by peer")
+func HasMessage(substr string) Condition {
+	return func(err error) bool {
+		return strings.Contains(err.Error(), substr)
+	}
+}
+
+// MatchesMessage returns a Condition that matches errors whose message matches re.
+//
+// Example:
+//
+//	cond := MatchesMessage(regexp.MustCompile(`^dial tcp .*: i/o timeout$`))
+func MatchesMessage(re *regexp.Regexp) Condition {
+	return func(err error) bool {
+		return re.MatchString(err.Error())
+	}
+}
+
+// And returns a Condition that matches when all of the given conditions match.
+// An empty list of conditions matches every error.
+//
+// Example:
+//
+//	cond := And(HasType[*net.OpError](), Not(IsAny(context.Canceled)))
+func And(conds ...Condition) Condition {
+	return func(err error) bool {
+		for _, cond := range conds {
+			if !cond(err) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Condition that matches when any of the given conditions matches.
+// An empty list of conditions matches no error.
+//
+// Example:
+//
+//	cond := Or(IsAny(io.EOF), HasMessage("unexpected end"))
+func Or(conds ...Condition) Condition {
+	return func(err error) bool {
+		for _, cond := range conds {
+			if cond(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Condition that matches when cond does not match.
+//
+// Example:
+//
+//	cond := Not(IsAny(context.Canceled))
+func Not(cond Condition) Condition {
+	return func(err error) bool {
+		return !cond(err)
+	}
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAny(t *testing.T) {
+	t.Run("IsAny matches any target", func(t *testing.T) {
+		cond := IsAny(context.Canceled, context.DeadlineExceeded)
+		assert.True(t, cond(context.Canceled))
+		assert.True(t, cond(context.DeadlineExceeded))
+		assert.True(t, cond(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)))
+		assert.False(t, cond(io.EOF))
+	})
+
+	t.Run("IsAny with no targets", func(t *testing.T) {
+		assert.False(t, IsAny()(io.EOF))
+	})
+}
+
+func TestHasType(t *testing.T) {
+	t.Run("HasType matches pointer type", func(t *testing.T) {
+		cond := HasType[*ValidationError]()
+		assert.True(t, cond(&ValidationError{Field: "name"}))
+		assert.True(t, cond(fmt.Errorf("wrapped: %w", &ValidationError{Field: "name"})))
+		assert.False(t, cond(&CustomError{Code: 400}))
+	})
+
+	t.Run("HasType matches value type", func(t *testing.T) {
+		cond := HasType[CustomError]()
+		assert.True(t, cond(CustomError{Code: 400}))
+		assert.False(t, cond(&CustomError{Code: 400}))
+	})
+}
+
+func TestHasMessage(t *testing.T) {
+	cond := HasMessage("connection reset")
+	assert.True(t, cond(errors.New("read tcp: connection reset by peer")))
+	assert.True(t, cond(fmt.Errorf("query failed: %w", errors.New("connection reset"))))
+	assert.False(t, cond(errors.New("connection refused")))
+}
+
+func TestMatchesMessage(t *testing.T) {
+	cond := MatchesMessage(regexp.MustCompile(`^dial tcp .*: i/o timeout$`))
+	assert.True(t, cond(errors.New("dial tcp 10.0.0.1:5432: i/o timeout")))
+	assert.False(t, cond(errors.New("read tcp 10.0.0.1:5432: i/o timeout")))
+}
+
+func TestConditionCombinators(t *testing.T) {
+	validationErr := fmt.Errorf("wrapped: %w", &ValidationError{Field: "email"})
+
+	t.Run("And requires all conditions", func(t *testing.T) {
+		cond := And(HasType[*ValidationError](), HasMessage("wrapped"))
+		assert.True(t, cond(validationErr))
+		assert.False(t, cond(&ValidationError{Field: "email"}))
+	})
+
+	t.Run("And with no conditions matches", func(t *testing.T) {
+		assert.True(t, And()(io.EOF))
+	})
+
+	t.Run("Or requires any condition", func(t *testing.T) {
+		cond := Or(IsAny(io.EOF), HasType[*ValidationError]())
+		assert.True(t, cond(io.EOF))
+		assert.True(t, cond(validationErr))
+		assert.False(t, cond(io.ErrUnexpectedEOF))
+	})
+
+	t.Run("Or with no conditions does not match", func(t *testing.T) {
+		assert.False(t, Or()(io.EOF))
+	})
+
+	t.Run("Not negates condition", func(t *testing.T) {
+		cond := Not(IsAny(context.Canceled))
+		assert.True(t, cond(io.EOF))
+		assert.False(t, cond(context.Canceled))
+	})
+
+	t.Run("Nested combinators", func(t *testing.T) {
+		cond := And(
+			Or(HasType[*ValidationError](), HasType[*CustomError]()),
+			Not(HasMessage("password")),
+		)
+		assert.True(t, cond(validationErr))
+		assert.True(t, cond(&CustomError{Code: 500, Message: "internal"}))
+		assert.False(t, cond(&CustomError{Code: 400, Message: "bad password"}))
+		assert.False(t, cond(io.EOF))
+	})
+
+	t.Run("Plain predicate as condition", func(t *testing.T) {
+		isTimeout := func(err error) bool {
+			var te interface{ Timeout() bool }
+			return errors.As(err, &te) && te.Timeout()
+		}
+		cond := Or(isTimeout, IsAny(context.DeadlineExceeded))
+		assert.True(t, cond(context.DeadlineExceeded))
+		assert.False(t, cond(io.EOF))
+	})
+}
@@ -0,0 +1,121 @@
+package errors
+
+import "context"
+
+// ContextDoneError describes an error returned while its context was done.
+// HandleCtx passes it to the matchers, so that cancellation handlers see the
+// context's error and cause rather than the error the operation returned,
+// which is often an unrelated I/O error caused by the cancellation.
+//
+// It unwraps to ContextErr and Cause only, not to Err, so errors.Is matches
+// context.Canceled or context.DeadlineExceeded and the cancellation cause.
+// Its message describes the context only, so that message matchers such as
+// OnMessage do not mistake it for Err.
+type ContextDoneError struct {
+	Err        error // Error returned by the operation
+	ContextErr error // ctx.Err(): context.Canceled or context.DeadlineExceeded
+	Cause      error // context.Cause(ctx), which equals ContextErr unless a cause was given
+}
+
+func (e *ContextDoneError) Error() string {
+	return "context done: " + e.Cause.Error()
+}
+
+func (e *ContextDoneError) Unwrap() []error {
+	if e.Cause == e.ContextErr { //nolint:errorlint // identity check
+		return []error{e.ContextErr}
+	}
+	return []error{e.ContextErr, e.Cause}
+}
+
+// OnCanceled creates an ErrorMatcher for errors caused by a canceled context,
+// that is errors matching context.Canceled with errors.Is.
+//
+// Example:
+//
+//	matcher := errors.OnCanceled(func(e error) error {
+//	    return nil // the client went away, nothing to report
+//	})
+func OnCanceled(handler ErrorHandler) ErrorMatcher {
+	return OnSentinel(context.Canceled, handler)
+}
+
+// OnDeadlineExceeded creates an ErrorMatcher for errors caused by an expired
+// context deadline, that is errors matching context.DeadlineExceeded with errors.Is.
+//
+// Example:
+//
+//	matcher := errors.OnDeadlineExceeded(func(e error) error {
+//	    return errors.WithKind(e, errors.DeadlineExceeded)
+//	})
+func OnDeadlineExceeded(handler ErrorHandler) ErrorMatcher {
+	return OnSentinel(context.DeadlineExceeded, handler)
+}
+
+// ContextCause returns the cancellation cause recorded by HandleCtx anywhere
+// in err's tree, as returned by context.Cause. It returns nil if err does not
+// contain a *ContextDoneError.
+//
+// Example:
+//
+//	errors.OnCanceled(func(e error) error {
+//	    if errors.Is(errors.ContextCause(e), ErrShutdown) {
+//	        return ErrUnavailable
+//	    }
+//	    return nil
+//	})
+func ContextCause(err error) error {
+	if done, ok := asType[*ContextDoneError](err); ok {
+		return done.Cause
+	}
+	return nil
+}
+
+// HandleCtx processes err against the matchers like Handle, taking the state
+// of ctx into account. If ctx is done, err is first wrapped in a
+// *ContextDoneError carrying ctx.Err() and context.Cause(ctx), and only the
+// matchers that match it, such as OnCanceled and OnDeadlineExceeded, are
+// considered, whatever their position in the list. Only if none matches is err
+// handled as Handle would.
+//
+// This makes cancellation handlers win even when the operation returned a
+// different error because of the cancellation, such as a closed connection,
+// so client disconnects are not reported as server errors.
+//
+// Example:
+//
+//	err := copyBody(ctx, w, r)
+//	handled, result := errors.HandleCtx(ctx, err,
+//	    errors.OnType(func(e *net.OpError) error {
+//	        return errors.WithKind(e, errors.Unavailable)
+//	    }),
+//	    errors.OnCanceled(func(e error) error {
+//	        return nil // the client disconnected
+//	    }),
+//	)
+func HandleCtx(ctx context.Context, err error, matchers ...ErrorMatcher) (bool, error) {
+	if handled, result := handleDone(ctx, err, matchers); handled {
+		return true, result
+	}
+	return HandleError(err, matchers...)
+}
+
+// handleDone handles err with the matchers that match it as a *ContextDoneError,
+// if ctx is done. It reports whether such a matcher was found.
+func handleDone(ctx context.Context, err error, matchers []ErrorMatcher) (bool, error) {
+	if err == nil || ctx.Err() == nil {
+		return false, nil
+	}
+
+	done := &ContextDoneError{
+		Err:        err,
+		ContextErr: ctx.Err(),
+		Cause:      context.Cause(ctx),
+	}
+	for _, matcher := range matchers {
+		if ok, result := matcher.try(done); ok {
+			return true, result
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextMatchers(t *testing.T) {
+	t.Run("OnCanceled", func(t *testing.T) {
+		matcher := OnCanceled(func(e error) error { return nil })
+		assert.True(t, matcher.Match(fmt.Errorf("read: %w", context.Canceled)))
+		assert.False(t, matcher.Match(context.DeadlineExceeded))
+	})
+
+	t.Run("OnDeadlineExceeded", func(t *testing.T) {
+		matcher := OnDeadlineExceeded(func(e error) error { return nil })
+		assert.True(t, matcher.Match(fmt.Errorf("read: %w", context.DeadlineExceeded)))
+		assert.False(t, matcher.Match(context.Canceled))
+	})
+}
+
+func TestContextDoneError(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+
+	t.Run("ContextDoneError without cause", func(t *testing.T) {
+		err := &ContextDoneError{Err: io.EOF, ContextErr: context.Canceled, Cause: context.Canceled}
+		require.EqualError(t, err, "context done: context canceled")
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, io.EOF)
+		assert.Equal(t, Canceled, KindOf(err))
+	})
+
+	t.Run("ContextDoneError with cause", func(t *testing.T) {
+		err := &ContextDoneError{Err: io.EOF, ContextErr: context.Canceled, Cause: errShutdown}
+		require.EqualError(t, err, "context done: shutting down")
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, errShutdown)
+	})
+
+	t.Run("ContextCause", func(t *testing.T) {
+		err := fmt.Errorf("handled: %w",
+			&ContextDoneError{Err: io.EOF, ContextErr: context.Canceled, Cause: errShutdown})
+		require.ErrorIs(t, ContextCause(err), errShutdown)
+		assert.NoError(t, ContextCause(io.EOF))
+	})
+}
+
+func TestHandleCtx(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+	errIO := errors.New("connection reset by peer")
+	matchers := []ErrorMatcher{
+		OnSentinel(errIO, func(e error) error { return fmt.Errorf("server error: %w", e) }),
+		OnCanceled(func(e error) error { return fmt.Errorf("client gone: %w", e) }),
+		OnDeadlineExceeded(func(e error) error { return errors.New("timeout") }),
+	}
+
+	t.Run("HandleCtx with live context", func(t *testing.T) {
+		handled, result := HandleCtx(t.Context(), errIO, matchers...)
+		assert.True(t, handled)
+		require.EqualError(t, result, "server error: connection reset by peer")
+	})
+
+	t.Run("HandleCtx with nil error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		handled, result := HandleCtx(ctx, nil, matchers...)
+		assert.True(t, handled)
+		assert.NoError(t, result)
+	})
+
+	t.Run("HandleCtx with canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		handled, result := HandleCtx(ctx, errIO, matchers...)
+		assert.True(t, handled)
+		require.EqualError(t, result, "client gone: context done: context canceled")
+
+		var done *ContextDoneError
+		require.ErrorAs(t, result, &done)
+		require.ErrorIs(t, done.Err, errIO)
+	})
+
+	t.Run("HandleCtx with cancellation cause", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(t.Context())
+		cancel(errShutdown)
+
+		handled, result := HandleCtx(ctx, errIO, matchers...)
+		assert.True(t, handled)
+		require.ErrorIs(t, result, errShutdown)
+		require.ErrorIs(t, ContextCause(result), errShutdown)
+	})
+
+	t.Run("HandleCtx with message matchers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		handled, result := HandleCtx(ctx, errIO, OnMessage("connection reset", func(e error) error {
+			return fmt.Errorf("reset: %w", e)
+		}))
+		assert.True(t, handled)
+		require.ErrorIs(t, result, errIO)
+		assert.NotErrorAs(t, result, new(*ContextDoneError))
+	})
+
+	t.Run("HandleCtx with expired deadline", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(t.Context(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		handled, result := HandleCtx(ctx, errIO, matchers...)
+		assert.True(t, handled)
+		require.EqualError(t, result, "timeout")
+	})
+
+	t.Run("HandleCtx without context matchers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		handled, result := HandleCtx(ctx, errIO, matchers[0])
+		assert.True(t, handled)
+		require.EqualError(t, result, "server error: connection reset by peer")
+
+		handled, result = HandleCtx(ctx, io.EOF, matchers[0])
+		assert.False(t, handled)
+		require.ErrorIs(t, result, io.EOF)
+	})
+}
+
+func TestHandlerHandleCtx(t *testing.T) {
+	handler := NewHandler(
+		OnSentinel(io.EOF, func(e error) error { return errors.New("eof") }),
+		OnCanceled(func(e error) error { return nil }),
+	).WithDefault(func(e error) error { return errors.New("default") })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	handled, result := handler.HandleCtx(ctx, io.EOF)
+	assert.True(t, handled)
+	require.NoError(t, result)
+
+	handled, result = handler.HandleCtx(t.Context(), io.EOF)
+	assert.True(t, handled)
+	require.EqualError(t, result, "eof")
+
+	handled, result = handler.WithDefault(nil).Extend().HandleCtx(t.Context(), io.ErrUnexpectedEOF)
+	assert.False(t, handled)
+	require.ErrorIs(t, result, io.ErrUnexpectedEOF)
+
+	_, result = handler.HandleCtx(t.Context(), io.ErrUnexpectedEOF)
+	require.EqualError(t, result, "default")
+}
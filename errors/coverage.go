@@ -0,0 +1,36 @@
+package errors
+
+// Uncovered returns the errors in errs that none of the matchers match, in the
+// order given. It returns nil if every error is covered.
+//
+// It is meant for tests that check a matcher set against the errors a function
+// documents, so that MustHandle cannot panic on a gap at run time. Custom error
+// types are checked by passing a value of the type, such as &ValidationError{}.
+// The go.aykhans.me/utils/errors/handlecheck analyzer reports the same gaps at
+// build time.
+//
+// Example:
+//
+//	// Load returns ErrNotFound, ErrTimeout or *ValidationError.
+//	func TestLoadErrorsHandled(t *testing.T) {
+//	    uncovered := errors.Uncovered(loadMatchers, ErrNotFound, ErrTimeout, &ValidationError{})
+//	    if len(uncovered) > 0 {
+//	        t.Errorf("errors not handled: %v", uncovered)
+//	    }
+//	}
+func Uncovered(matchers []ErrorMatcher, errs ...error) []error {
+	var uncovered []error
+	for _, err := range errs {
+		covered := false
+		for _, matcher := range matchers {
+			if matcher.Match(err) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, err)
+		}
+	}
+	return uncovered
+}
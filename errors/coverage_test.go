@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUncovered(t *testing.T) {
+	matchers := []ErrorMatcher{
+		OnSentinel(ErrSentinel1, func(e error) error { return nil }),
+		OnType(func(e *ValidationError) error { return nil }),
+	}
+
+	t.Run("Uncovered with all errors covered", func(t *testing.T) {
+		uncovered := Uncovered(matchers,
+			ErrSentinel1,
+			&ValidationError{},
+			fmt.Errorf("wrapped: %w", ErrSentinel1),
+		)
+		assert.Empty(t, uncovered)
+	})
+
+	t.Run("Uncovered with gaps", func(t *testing.T) {
+		uncovered := Uncovered(matchers, ErrSentinel1, ErrSentinel2, io.EOF, CustomError{})
+		assert.Equal(t, []error{ErrSentinel2, io.EOF, CustomError{}}, uncovered)
+	})
+
+	t.Run("Uncovered without matchers", func(t *testing.T) {
+		assert.Equal(t, []error{io.EOF}, Uncovered(nil, io.EOF))
+	})
+
+	t.Run("Uncovered without errors", func(t *testing.T) {
+		assert.Nil(t, Uncovered(matchers))
+	})
+}
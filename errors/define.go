@@ -0,0 +1,127 @@
+package errors
+
+import (
+	"cmp"
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Const is an error type for sentinel errors declared as constants.
+// Unlike variables holding errors created with New, constants cannot be
+// reassigned by other packages, and they stay comparable with == and errors.Is.
+//
+// Two Const values with the same message are the same error, so each sentinel
+// should have a distinct message.
+//
+// Example:
+//
+//	const ErrNotFound = errors.Const("user not found")
+//
+//	if errors.Is(err, ErrNotFound) {
+//	    ...
+//	}
+type Const string
+
+func (c Const) Error() string {
+	return string(c)
+}
+
+// Definition describes a sentinel error declared with Define.
+type Definition struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Message     string `json:"message"` // Message of the sentinel
+	Package     string `json:"package"` // Import path of the package that called Define
+	Sentinel    error  `json:"-"`
+}
+
+// Define registers sentinel with a stable identity code, as Register does, and
+// records its description and declaring package, so that all sentinels can be
+// listed with Definitions, for example to generate API error documentation.
+// It returns the sentinel, so it can be used directly in a variable declaration.
+//
+// Define panics in the same cases as Register. It is intended to be called
+// during package initialization.
+//
+// Example:
+//
+//	const ErrNotFound = errors.Const("user not found")
+//
+//	var _ = errors.Define("users.not_found", "The requested user does not exist.", ErrNotFound)
+//
+//	var ErrBanned = errors.Define("users.banned", "The user is banned.", errors.New("user banned"))
+func Define(code, description string, sentinel error) error {
+	Register(code, sentinel)
+
+	def := Definition{
+		Code:        code,
+		Description: description,
+		Message:     sentinel.Error(),
+		Package:     callerPackage(1),
+		Sentinel:    sentinel,
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.defs[code] = def
+	return sentinel
+}
+
+// Definitions returns every sentinel declared with Define, ordered by package
+// and then by code.
+//
+// Example:
+//
+//	for _, def := range errors.Definitions() {
+//	    fmt.Printf("| %s | %s | %s |\n", def.Code, def.Message, def.Description)
+//	}
+func Definitions() []Definition {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	defs := make([]Definition, 0, len(registry.defs))
+	for _, def := range registry.defs {
+		defs = append(defs, def)
+	}
+	slices.SortFunc(defs, func(a, b Definition) int {
+		return cmp.Or(
+			strings.Compare(a.Package, b.Package),
+			strings.Compare(a.Code, b.Code),
+		)
+	})
+	return defs
+}
+
+// callerPackage returns the import path of the package of a function on the
+// call stack: the caller of callerPackage for skip 0, its caller for 1, and so on.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	return funcPackage(fn.Name())
+}
+
+// funcPackage returns the import path of the package of the function named
+// name, as reported by runtime.Func.Name.
+func funcPackage(name string) string {
+	// Function names look like "example.com/mod/pkg.Func" or
+	// "example.com/mod/pkg.(*Type).Method"; the package path ends at the
+	// first dot after the last slash. Dots in the last element of the path
+	// are escaped, as in "gopkg.in/yaml%2ev3.init".
+	slash := strings.LastIndexByte(name, '/') + 1
+	if dot := strings.IndexByte(name[slash:], '.'); dot >= 0 {
+		name = name[:slash+dot]
+	}
+	if path, err := url.PathUnescape(name); err == nil {
+		return path
+	}
+	return name
+}
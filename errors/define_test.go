@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	errConstNotFound = Const("const: not found")
+	errConstBanned   = Const("const: banned")
+)
+
+var (
+	errDefineSentinel = New("defined")
+	errDefined        = Define("test.define.returns", "Returned.", errDefineSentinel)
+
+	_ = Define("test.define.not_found", "The user does not exist.", errConstNotFound)
+	_ = Define("test.define.banned", "The user is banned.", errConstBanned)
+)
+
+func TestConst(t *testing.T) {
+	t.Run("Const message", func(t *testing.T) {
+		require.EqualError(t, errConstNotFound, "const: not found")
+	})
+
+	t.Run("Const comparison", func(t *testing.T) {
+		err := fmt.Errorf("load: %w", errConstNotFound)
+		require.ErrorIs(t, err, errConstNotFound)
+		require.NotErrorIs(t, err, errConstBanned)
+		assert.Equal(t, error(errConstNotFound), error(Const("const: not found")))
+	})
+
+	t.Run("Const with matchers", func(t *testing.T) {
+		handled, result := Handle(Wrap(errConstBanned, "login"),
+			OnSentinel(errConstNotFound, func(e error) error { return nil }),
+			OnSentinel(errConstBanned, func(e error) error { return fmt.Errorf("forbidden") }),
+		)
+		assert.True(t, handled)
+		require.EqualError(t, result, "forbidden")
+	})
+}
+
+func TestDefine(t *testing.T) {
+	testDefinitions := func() []Definition {
+		var defs []Definition
+		for _, def := range Definitions() {
+			if strings.HasPrefix(def.Code, "test.define.") {
+				defs = append(defs, def)
+			}
+		}
+		return defs
+	}
+
+	t.Run("Define returns sentinel", func(t *testing.T) {
+		assert.Same(t, errDefineSentinel, errDefined)
+	})
+
+	t.Run("Define registers the code", func(t *testing.T) {
+		code, ok := CodeOf(fmt.Errorf("load: %w", errConstNotFound))
+		assert.True(t, ok)
+		assert.Equal(t, "test.define.not_found", code)
+	})
+
+	t.Run("Define panics like Register", func(t *testing.T) {
+		assert.PanicsWithValue(t, "errors: Register called with empty code", func() {
+			Define("", "Empty.", errConstBanned)
+		})
+		assert.PanicsWithValue(t, `errors: code "test.define.not_found" is already registered`, func() {
+			Define("test.define.not_found", "Duplicate.", errConstBanned)
+		})
+	})
+
+	t.Run("Definitions lists defined sentinels", func(t *testing.T) {
+		defs := testDefinitions()
+		require.Len(t, defs, 3)
+		assert.Equal(t, Definition{
+			Code:        "test.define.banned",
+			Description: "The user is banned.",
+			Message:     "const: banned",
+			Package:     "go.aykhans.me/utils/errors",
+			Sentinel:    errConstBanned,
+		}, defs[0])
+		assert.Equal(t, "test.define.not_found", defs[1].Code)
+		assert.Equal(t, "go.aykhans.me/utils/errors", defs[1].Package)
+		assert.Equal(t, "test.define.returns", defs[2].Code)
+	})
+
+	t.Run("Definition JSON", func(t *testing.T) {
+		data, err := json.Marshal(Definition{
+			Code:        "users.banned",
+			Description: "The user is banned.",
+			Message:     "banned",
+			Package:     "example.com/users",
+			Sentinel:    errConstBanned,
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"code": "users.banned",
+			"description": "The user is banned.",
+			"message": "banned",
+			"package": "example.com/users"
+		}`, string(data))
+	})
+
+	t.Run("Defined Const survives serialization", func(t *testing.T) {
+		data, err := json.Marshal(Serialize(Wrap(errConstNotFound, "load")))
+		require.NoError(t, err)
+
+		var s SerializedError
+		require.NoError(t, json.Unmarshal(data, &s))
+		decoded := s.Err()
+
+		require.ErrorIs(t, decoded, errConstNotFound)
+		handled, _ := Handle(decoded, OnSentinel(errConstNotFound, func(e error) error { return nil }))
+		assert.True(t, handled)
+	})
+}
+
+func TestCallerPackage(t *testing.T) {
+	assert.Equal(t, "go.aykhans.me/utils/errors", callerPackage(0))
+	assert.Equal(t, "testing", callerPackage(1))
+	func() {
+		assert.Equal(t, "go.aykhans.me/utils/errors", callerPackage(0))
+	}()
+}
+
+func TestFuncPackage(t *testing.T) {
+	tests := map[string]string{
+		"main.main":                          "main",
+		"example.com/mod/pkg.Func":           "example.com/mod/pkg",
+		"example.com/mod/pkg.(*Type).Method": "example.com/mod/pkg",
+		"example.com/mod/pkg.init.func1":     "example.com/mod/pkg",
+		"gopkg.in/yaml%2ev3.init":            "gopkg.in/yaml.v3",
+		"example.com/bad%zzpath.Func":        "example.com/bad%zzpath",
+	}
+	for name, want := range tests {
+		t.Run("funcPackage with "+name, func(t *testing.T) {
+			assert.Equal(t, want, funcPackage(name))
+		})
+	}
+}
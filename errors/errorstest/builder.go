@@ -0,0 +1,97 @@
+package errorstest
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"go.aykhans.me/utils/errors"
+)
+
+// Builder builds error trees for tests, layer by layer, from the innermost
+// error outwards. Each method returns a new Builder, so a common base can be
+// reused for several trees.
+//
+// Example:
+//
+//	err := errorstest.From(ErrNotFound).
+//	    Wrap("query users").
+//	    With("user_id", 42).
+//	    Join(io.EOF, &ValidationError{}).
+//	    Wrap("handle request").
+//	    Err()
+type Builder struct {
+	err error
+}
+
+// From returns a Builder whose innermost error is err.
+func From(err error) Builder {
+	return Builder{err: err}
+}
+
+// New returns a Builder whose innermost error is a plain error with the given message.
+func New(message string) Builder {
+	return From(stderrors.New(message))
+}
+
+// Wrap adds a layer with the given message, as fmt.Errorf("message: %w", err) would.
+func (b Builder) Wrap(message string) Builder {
+	return From(fmt.Errorf("%s: %w", message, b.err))
+}
+
+// WrapStack adds a layer with the given message that records the call stack,
+// as errors.Wrap would.
+func (b Builder) WrapStack(message string) Builder {
+	return From(errors.Wrap(b.err, message))
+}
+
+// With adds a layer carrying structured fields, as errors.With would.
+func (b Builder) With(args ...any) Builder {
+	return From(errors.With(b.err, args...))
+}
+
+// WithKind adds a layer carrying kind, as errors.WithKind would.
+func (b Builder) WithKind(kind errors.Kind) Builder {
+	return From(errors.WithKind(b.err, kind))
+}
+
+// Join joins the current error with errs, as errors.Join would.
+func (b Builder) Join(errs ...error) Builder {
+	return From(errors.Join(append([]error{b.err}, errs...)...))
+}
+
+// Err returns the built error.
+func (b Builder) Err() error {
+	return b.err
+}
+
+// Deep returns leaf wrapped in depth layers, named "layer 1" for the innermost
+// to "layer <depth>" for the outermost, for testing code that walks long chains.
+//
+// Example:
+//
+//	err := errorstest.Deep(io.EOF, 100)
+//	errors.Is(err, io.EOF) // true
+func Deep(leaf error, depth int) error {
+	b := From(leaf)
+	for i := 1; i <= depth; i++ {
+		b = b.Wrap(fmt.Sprintf("layer %d", i))
+	}
+	return b.Err()
+}
+
+// Wide returns the leaves joined together, each wrapped in a layer named
+// "branch <i>" counting from 1, for testing code that handles joined errors.
+//
+// Example:
+//
+//	err := errorstest.Wide(io.EOF, ErrNotFound, &ValidationError{})
+//	// branch 1: EOF
+//	// branch 2: not found
+//	// branch 3: validation failed
+func Wide(leaves ...error) error {
+	branches := make([]error, len(leaves))
+	for i, leaf := range leaves {
+		branches[i] = From(leaf).Wrap(fmt.Sprintf("branch %d", i+1)).Err()
+	}
+	return errors.Join(branches...)
+}
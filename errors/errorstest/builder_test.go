@@ -0,0 +1,66 @@
+package errorstest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.aykhans.me/utils/errors"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("Builder layers", func(t *testing.T) {
+		base := New("leaf")
+		err := base.
+			Wrap("query").
+			With("user_id", 42).
+			WithKind(errors.NotFound).
+			WrapStack("load").
+			Err()
+
+		require.EqualError(t, err, "load: query: leaf")
+		require.ErrorIs(t, err, base.Err())
+		assert.Equal(t, errors.NotFound, errors.KindOf(err))
+		assert.Len(t, errors.Fields(err), 1)
+		assert.Contains(t, fmt.Sprintf("%+v", err), "TestBuilder")
+	})
+
+	t.Run("Builder is immutable", func(t *testing.T) {
+		base := From(io.EOF).Wrap("base")
+		first := base.Wrap("first").Err()
+		second := base.Wrap("second").Err()
+
+		require.EqualError(t, base.Err(), "base: EOF")
+		require.EqualError(t, first, "first: base: EOF")
+		require.EqualError(t, second, "second: base: EOF")
+	})
+
+	t.Run("Builder Join", func(t *testing.T) {
+		err := From(io.EOF).Join(io.ErrUnexpectedEOF, nil).Err()
+		require.EqualError(t, err, "EOF\nunexpected EOF")
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("New returns distinct errors", func(t *testing.T) {
+		require.NotErrorIs(t, New("same").Err(), New("same").Err())
+	})
+}
+
+func TestDeep(t *testing.T) {
+	err := Deep(io.EOF, 3)
+	require.EqualError(t, err, "layer 3: layer 2: layer 1: EOF")
+	require.ErrorIs(t, Deep(io.EOF, 1000), io.EOF)
+	assert.Same(t, io.EOF, Deep(io.EOF, 0))
+}
+
+func TestWide(t *testing.T) {
+	err := Wide(io.EOF, errNotFound, &validationError{Field: "age"})
+	require.EqualError(t, err, "branch 1: EOF\nbranch 2: not found\nbranch 3: invalid age")
+
+	unhandled, _ := errors.HandleAll(err, newTestHandler().Matchers()...)
+	assert.Empty(t, unhandled)
+
+	assert.NoError(t, Wide())
+}
@@ -0,0 +1,127 @@
+// Package errorstest provides test assertions for code that handles errors with
+// go.aykhans.me/utils/errors, and builders for the error trees such code sees.
+//
+// The assertions report failures with t.Errorf and return whether they passed,
+// like the testify assert package. They accept any testing.TB.
+package errorstest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.aykhans.me/utils/errors"
+)
+
+// TB is the subset of testing.TB used by the assertions.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertHandledBy asserts that the first matcher of handler that matches err
+// is the one with the given name, without running any handler.
+//
+// Example:
+//
+//	errorstest.AssertHandledBy(t, fmt.Errorf("load: %w", sql.ErrNoRows), api.Handler, "not_found")
+func AssertHandledBy(t TB, err error, handler *errors.Handler, name string) bool {
+	t.Helper()
+
+	matcher, ok := handler.MatcherFor(err)
+	switch {
+	case !ok:
+		t.Errorf("error %q is not matched by any matcher, want matcher %q", describe(err), name)
+		return false
+	case matcher.Name != name:
+		t.Errorf("error %q is matched by matcher %q, want matcher %q", describe(err), matcher.Name, name)
+		return false
+	}
+	return true
+}
+
+// AssertUnhandled asserts that no matcher of handler matches err.
+// The handler's default handler, if any, is not taken into account.
+func AssertUnhandled(t TB, err error, handler *errors.Handler) bool {
+	t.Helper()
+
+	if matcher, ok := handler.MatcherFor(err); ok {
+		t.Errorf("error %q is matched by matcher %q, want no match", describe(err), matcher.Name)
+		return false
+	}
+	return true
+}
+
+// AssertCovers asserts that every error in errs is matched by one of the
+// matchers. Custom error types are checked by passing a value of the type.
+//
+// Example:
+//
+//	errorstest.AssertCovers(t, api.Handler.Matchers(), ErrNotFound, ErrTimeout, &ValidationError{})
+func AssertCovers(t TB, matchers []errors.ErrorMatcher, errs ...error) bool {
+	t.Helper()
+
+	uncovered := errors.Uncovered(matchers, errs...)
+	if len(uncovered) == 0 {
+		return true
+	}
+
+	descriptions := make([]string, len(uncovered))
+	for i, err := range uncovered {
+		descriptions[i] = fmt.Sprintf("%q (%T)", describe(err), err)
+	}
+	t.Errorf("errors not matched by any matcher: %s", strings.Join(descriptions, ", "))
+	return false
+}
+
+// AssertChainContains asserts that err's tree contains every target.
+// A target that is a nil pointer of an error type, such as (*MyErr)(nil),
+// asserts that the tree contains an error of that type, as errors.As would.
+// Any other target is a sentinel that must match with errors.Is.
+//
+// Example:
+//
+//	errorstest.AssertChainContains(t, err, ErrNotFound, (*ValidationError)(nil))
+func AssertChainContains(t TB, err error, targets ...error) bool {
+	t.Helper()
+
+	var missing []string
+	for _, target := range targets {
+		if typ, ok := typeTarget(target); ok {
+			if !errors.As(err, reflect.New(typ).Interface()) {
+				missing = append(missing, "an error of type "+typ.String())
+			}
+			continue
+		}
+		if !errors.Is(err, target) {
+			missing = append(missing, fmt.Sprintf("%q", describe(target)))
+		}
+	}
+
+	if len(missing) > 0 {
+		t.Errorf("error %q does not contain %s", describe(err), strings.Join(missing, ", "))
+		return false
+	}
+	return true
+}
+
+// typeTarget reports whether target is a nil pointer of an error type, and
+// returns that type.
+func typeTarget(target error) (reflect.Type, bool) {
+	if target == nil {
+		return nil, false
+	}
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || !value.IsNil() {
+		return nil, false
+	}
+	return value.Type(), true
+}
+
+// describe returns the message of err, or "<nil>".
+func describe(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
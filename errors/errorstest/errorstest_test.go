@@ -0,0 +1,124 @@
+package errorstest
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.aykhans.me/utils/errors"
+)
+
+// recorder is a TB that records failures instead of failing the test.
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+type validationError struct {
+	Field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.Field
+}
+
+var errNotFound = errors.New("not found")
+
+func newTestHandler() *errors.Handler {
+	return errors.NewHandler(
+		errors.OnSentinel(errNotFound, func(e error) error { return nil }).WithName("not_found"),
+		errors.OnType(func(e *validationError) error { return e }).WithName("validation"),
+		errors.OnSentinel(io.EOF, func(e error) error { return nil }),
+	)
+}
+
+func TestAssertHandledBy(t *testing.T) {
+	handler := newTestHandler()
+
+	t.Run("AssertHandledBy with expected matcher", func(t *testing.T) {
+		rec := &recorder{}
+		assert.True(t, AssertHandledBy(rec, fmt.Errorf("load: %w", errNotFound), handler, "not_found"))
+		assert.True(t, AssertHandledBy(rec, &validationError{}, handler, "validation"))
+		assert.True(t, AssertHandledBy(rec, io.EOF, handler, ""))
+		assert.Empty(t, rec.failures)
+	})
+
+	t.Run("AssertHandledBy with other matcher", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertHandledBy(rec, errNotFound, handler, "validation"))
+		assert.Equal(t, []string{
+			`error "not found" is matched by matcher "not_found", want matcher "validation"`,
+		}, rec.failures)
+	})
+
+	t.Run("AssertHandledBy without match", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertHandledBy(rec, io.ErrUnexpectedEOF, handler, "not_found"))
+		assert.Equal(t, []string{
+			`error "unexpected EOF" is not matched by any matcher, want matcher "not_found"`,
+		}, rec.failures)
+	})
+}
+
+func TestAssertUnhandled(t *testing.T) {
+	handler := newTestHandler()
+	rec := &recorder{}
+
+	assert.True(t, AssertUnhandled(rec, io.ErrUnexpectedEOF, handler))
+	assert.False(t, AssertUnhandled(rec, errNotFound, handler))
+	assert.Equal(t, []string{`error "not found" is matched by matcher "not_found", want no match`}, rec.failures)
+}
+
+func TestAssertCovers(t *testing.T) {
+	matchers := newTestHandler().Matchers()
+
+	t.Run("AssertCovers with all errors covered", func(t *testing.T) {
+		rec := &recorder{}
+		assert.True(t, AssertCovers(rec, matchers, errNotFound, &validationError{}, io.EOF))
+		assert.Empty(t, rec.failures)
+	})
+
+	t.Run("AssertCovers with gaps", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertCovers(rec, matchers, errNotFound, io.ErrUnexpectedEOF, io.ErrClosedPipe))
+		assert.Equal(t, []string{
+			`errors not matched by any matcher: "unexpected EOF" (*errors.errorString), ` +
+				`"io: read/write on closed pipe" (*errors.errorString)`,
+		}, rec.failures)
+	})
+}
+
+func TestAssertChainContains(t *testing.T) {
+	err := From(errNotFound).Wrap("query").Join(&validationError{Field: "age"}).Wrap("handle").Err()
+
+	t.Run("AssertChainContains with sentinels and types", func(t *testing.T) {
+		rec := &recorder{}
+		assert.True(t, AssertChainContains(rec, err, errNotFound, (*validationError)(nil)))
+		assert.True(t, AssertChainContains(rec, err))
+		assert.Empty(t, rec.failures)
+	})
+
+	t.Run("AssertChainContains with missing targets", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertChainContains(rec, io.EOF, errNotFound, (*validationError)(nil)))
+		assert.Equal(t, []string{
+			`error "EOF" does not contain "not found", an error of type *errorstest.validationError`,
+		}, rec.failures)
+	})
+
+	t.Run("AssertChainContains with nil error", func(t *testing.T) {
+		rec := &recorder{}
+		assert.False(t, AssertChainContains(rec, nil, io.EOF))
+		assert.Equal(t, []string{`error "<nil>" does not contain "EOF"`}, rec.failures)
+	})
+
+	t.Run("AssertChainContains with testing.T", func(t *testing.T) {
+		AssertChainContains(t, err, errNotFound)
+	})
+}
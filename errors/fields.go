@@ -0,0 +1,106 @@
+package errors
+
+import "log/slog"
+
+// fieldCarrier is implemented by errors that carry structured fields of their own.
+type fieldCarrier interface {
+	ownFields() []slog.Attr
+}
+
+// fieldsError is an error annotated with structured key/value fields.
+// The fields are not part of the error message.
+type fieldsError struct {
+	wrapper
+
+	attrs []slog.Attr
+}
+
+func (e *fieldsError) ownFields() []slog.Attr {
+	return e.attrs
+}
+
+// LogValue implements slog.LogValuer. See LogValue for the layout.
+// It starts from e rather than the embedded wrapper, so that e's own fields
+// are included.
+func (e *fieldsError) LogValue() slog.Value {
+	return LogValue(e)
+}
+
+// With attaches structured fields to err and returns the annotated error.
+// The arguments are interpreted like the arguments of slog.Logger.Info: either
+// alternating keys and values, or slog.Attr values. The error message is left
+// unchanged, and the result unwraps to err so errors.Is, errors.As and Handle
+// still see the original error. If err is nil, With returns nil.
+//
+// Fields are collected from the whole wrap chain by Fields, and logged as
+// structured attributes when the error is passed to a slog.Logger.
+//
+// Example:
+//
+//	if err := chargeCard(ctx, order); err != nil {
+//	    return errors.With(err, "order_id", order.ID, "attempt", attempt)
+//	}
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldsError{wrapper{err}, slog.Group("", args...).Value.Group()}
+}
+
+// Fields returns the fields attached with With anywhere in err's tree.
+// Fields closer to the top of the chain come first, and when the same key is
+// attached more than once, the outermost value wins.
+// It returns nil if err carries no fields.
+//
+// Example:
+//
+//	err := errors.With(errors.With(io.EOF, "user_id", 42), "order_id", 7)
+//	errors.Fields(err) // [order_id=7 user_id=42]
+func Fields(err error) []slog.Attr {
+	var attrs []slog.Attr
+	seen := make(map[string]struct{})
+	walk(err, func(e error) bool {
+		if fc, ok := e.(fieldCarrier); ok {
+			for _, attr := range fc.ownFields() {
+				if _, dup := seen[attr.Key]; dup {
+					continue
+				}
+				seen[attr.Key] = struct{}{}
+				attrs = append(attrs, attr)
+			}
+		}
+		return true
+	})
+	return attrs
+}
+
+// LogValue returns the slog representation of err.
+// If err carries fields, the value is a group holding the error message under
+// the "msg" key followed by the fields returned by Fields. Otherwise it is the
+// error message as a string.
+//
+// Errors created by With, New, Wrap and Wrapf implement slog.LogValuer using this
+// function. LogValue is useful when the outermost layer is another wrapper, such
+// as one created by fmt.Errorf.
+//
+// Example:
+//
+//	logger.Error("payment failed", "err", err)
+//	// level=ERROR msg="payment failed" err.msg="charge card: declined" err.order_id=7 err.attempt=2
+//
+//	logger.Error("payment failed", "err", errors.LogValue(fmt.Errorf("retry: %w", err)))
+func LogValue(err error) slog.Value {
+	if err == nil {
+		return slog.Value{}
+	}
+
+	fields := Fields(err)
+	if len(fields) == 0 {
+		return slog.StringValue(err.Error())
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	attrs = append(attrs, slog.String("msg", err.Error()))
+	attrs = append(attrs, fields...)
+	return slog.GroupValue(attrs...)
+}
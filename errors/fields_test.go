@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWith(t *testing.T) {
+	t.Run("With nil error", func(t *testing.T) {
+		assert.NoError(t, With(nil, "user_id", 42))
+	})
+
+	t.Run("With keeps message", func(t *testing.T) {
+		err := With(io.EOF, "user_id", 42)
+		assert.EqualError(t, err, "EOF")
+	})
+
+	t.Run("With works with errors.Is, errors.As and Handle", func(t *testing.T) {
+		customErr := &CustomError{Code: 409, Message: "conflict"}
+		err := With(fmt.Errorf("save: %w", customErr), "order_id", 7)
+
+		require.ErrorIs(t, err, customErr)
+		var target *CustomError
+		require.ErrorAs(t, err, &target)
+
+		handled, result := Handle(err, OnType(func(e *CustomError) error {
+			return fmt.Errorf("code %d", e.Code)
+		}))
+		assert.True(t, handled)
+		assert.EqualError(t, result, "code 409")
+	})
+
+	t.Run("With accepts slog.Attr values", func(t *testing.T) {
+		err := With(io.EOF, slog.Int("attempt", 3), "user_id", "u-1")
+		assert.Equal(t, []slog.Attr{
+			slog.Int("attempt", 3),
+			slog.String("user_id", "u-1"),
+		}, Fields(err))
+	})
+
+	t.Run("With formats wrapped error", func(t *testing.T) {
+		err := With(New("connection refused"), "host", "db")
+		assert.Equal(t, "connection refused", fmt.Sprintf("%v", err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "fields_test.go:")
+	})
+}
+
+func TestFields(t *testing.T) {
+	t.Run("Fields of nil error", func(t *testing.T) {
+		assert.Empty(t, Fields(nil))
+	})
+
+	t.Run("Fields of error without fields", func(t *testing.T) {
+		assert.Empty(t, Fields(fmt.Errorf("wrapped: %w", io.EOF)))
+	})
+
+	t.Run("Fields collected across wrap chain", func(t *testing.T) {
+		err := With(io.EOF, "user_id", 42)
+		err = Wrap(err, "load user")
+		err = fmt.Errorf("handle request: %w", With(err, "request_id", "r-1"))
+
+		assert.Equal(t, []slog.Attr{
+			slog.String("request_id", "r-1"),
+			slog.Int("user_id", 42),
+		}, Fields(err))
+	})
+
+	t.Run("Fields outermost value wins", func(t *testing.T) {
+		err := With(With(io.EOF, "attempt", 1, "user_id", 42), "attempt", 2)
+		assert.Equal(t, []slog.Attr{
+			slog.Int("attempt", 2),
+			slog.Int("user_id", 42),
+		}, Fields(err))
+	})
+
+	t.Run("Fields collected across joined errors", func(t *testing.T) {
+		err := errors.Join(With(io.EOF, "row", 1), With(io.EOF, "row_id", "r-2"))
+		assert.Equal(t, []slog.Attr{
+			slog.Int("row", 1),
+			slog.String("row_id", "r-2"),
+		}, Fields(err))
+	})
+}
+
+func TestLogValue(t *testing.T) {
+	t.Run("LogValue without fields", func(t *testing.T) {
+		value := LogValue(io.EOF)
+		assert.Equal(t, slog.KindString, value.Kind())
+		assert.Equal(t, "EOF", value.String())
+	})
+
+	t.Run("LogValue with fields", func(t *testing.T) {
+		value := LogValue(fmt.Errorf("retry: %w", With(io.EOF, "attempt", 2)))
+		require.Equal(t, slog.KindGroup, value.Kind())
+		assert.Equal(t, []slog.Attr{
+			slog.String("msg", "retry: EOF"),
+			slog.Int("attempt", 2),
+		}, value.Group())
+	})
+
+	t.Run("Logger logs fields as structured attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+		err := Wrap(With(io.EOF, "user_id", 42, "order_id", "o-7"), "charge card")
+		logger.Error("payment failed", "err", err)
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, map[string]any{
+			"msg":      "charge card: EOF",
+			"user_id":  float64(42),
+			"order_id": "o-7",
+		}, record["err"])
+	})
+
+	t.Run("Logger logs the fields of an outermost With", func(t *testing.T) {
+		value := slog.AnyValue(With(io.EOF, "attempt", 2)).Resolve()
+		assert.Equal(t, slog.KindGroup, value.Kind())
+		assert.Equal(t, []slog.Attr{slog.String("msg", "EOF"), slog.Int("attempt", 2)}, value.Group())
+	})
+
+	t.Run("Logger logs plain stack errors as strings", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+		logger.Error("failed", "err", Wrap(io.EOF, "read"))
+		assert.True(t, strings.HasSuffix(strings.TrimSpace(buf.String()), `err="read: EOF"`))
+	})
+}
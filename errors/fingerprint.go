@@ -0,0 +1,276 @@
+package errors
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default values used by NewAggregator for zero fields of AggregatorOptions.
+const (
+	defaultAggregatorWindow  = time.Hour
+	defaultAggregatorSamples = 3
+	aggregatorBuckets        = 60
+)
+
+// variableParts matches the parts of an error message that vary between
+// occurrences of the same error, with the placeholders they are replaced with.
+// The patterns are applied in order, so more specific ones come first.
+var variableParts = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\[(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}\](?::\d+)?`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>"},
+}
+
+// hexWord matches words made of hexadecimal digits. Only the ones that mix
+// digits and letters are replaced, so plain words and numbers are left alone.
+var hexWord = regexp.MustCompile(`(?i)\b[0-9a-f]{6,}\b`)
+
+// number matches integers and decimals.
+var number = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+// MessageTemplate returns msg with its variable parts replaced with placeholders,
+// so that messages of the same error compare equal. The variable parts are
+// UUIDs, IP addresses, hexadecimal values such as hashes and pointers, and numbers.
+//
+// Example:
+//
+//	errors.MessageTemplate("user 42 not found on 10.0.0.7:5432")
+//	// "user <n> not found on <ip>"
+func MessageTemplate(msg string) string {
+	for _, part := range variableParts {
+		msg = part.re.ReplaceAllString(msg, part.placeholder)
+	}
+	msg = hexWord.ReplaceAllStringFunc(msg, func(word string) string {
+		if strings.ContainsAny(word, "0123456789") && strings.ContainsAny(word, "abcdefABCDEF") {
+			return "<hex>"
+		}
+		return word
+	})
+	return number.ReplaceAllString(msg, "<n>")
+}
+
+// Fingerprint returns a short, stable identifier for the kind of error err is,
+// suitable for grouping similar errors in alerts. It hashes the types and
+// registered sentinel codes of every error in err's tree, together with the
+// template of the message as returned by MessageTemplate, so that errors that
+// only differ by IDs, numbers or addresses share a fingerprint.
+//
+// The fingerprint is deterministic across processes, and errors rebuilt with
+// SerializedError.Err have the same fingerprint as the original.
+// It returns the empty string if err is nil.
+//
+// Example:
+//
+//	errors.Fingerprint(fmt.Errorf("load user %d: %w", 42, ErrNotFound)) ==
+//	    errors.Fingerprint(fmt.Errorf("load user %d: %w", 7, ErrNotFound)) // true
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n", MessageTemplate(err.Error()))
+	walk(err, func(e error) bool {
+		code, _ := codeOf(e)
+		fmt.Fprintf(hash, "%s|%s\n", typeName(e), code)
+		return true
+	})
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// typeName returns the Go type of err, or the original type for errors rebuilt
+// with SerializedError.Err.
+func typeName(err error) string {
+	if st, ok := err.(interface{ serializedType() string }); ok {
+		return st.serializedType()
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// AggregatorOptions configures an Aggregator.
+// Zero values select the defaults noted on each field.
+type AggregatorOptions struct {
+	// Window is the sliding window occurrences are counted over. Defaults to 1h.
+	Window time.Duration
+	// Samples is the number of distinct recent messages kept per group. Defaults to 3.
+	Samples int
+	// Clock is used to timestamp occurrences. Nil means the system clock.
+	Clock Clock
+}
+
+// ErrorGroup describes the occurrences of errors sharing a fingerprint.
+type ErrorGroup struct {
+	Fingerprint string    `json:"fingerprint"`
+	Type        string    `json:"type"`     // Type of the outermost error
+	Template    string    `json:"template"` // Message template, see MessageTemplate
+	Count       int       `json:"count"`    // Occurrences within the window
+	FirstSeen   time.Time `json:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen"`
+	Samples     []string  `json:"samples"` // Most recent distinct messages, newest first
+}
+
+// bucket counts the occurrences of a group within a slice of the window.
+type bucket struct {
+	start time.Time
+	count int
+}
+
+// errorGroup is the state of an ErrorGroup within an Aggregator.
+type errorGroup struct {
+	ErrorGroup
+
+	buckets []bucket // Oldest first
+}
+
+// Aggregator counts errors per fingerprint over a sliding window and keeps
+// sample messages, for alerting and status pages.
+// It is safe for concurrent use and must be created with NewAggregator.
+type Aggregator struct {
+	opts        AggregatorOptions
+	bucketWidth time.Duration
+
+	mu     sync.Mutex
+	groups map[string]*errorGroup
+	pruned time.Time // Last time expired groups were forgotten
+}
+
+// NewAggregator returns an empty Aggregator.
+//
+// Example:
+//
+//	agg := errors.NewAggregator(errors.AggregatorOptions{Window: 15 * time.Minute})
+//
+//	// In the error path
+//	agg.Add(err)
+//
+//	// On the status page
+//	_ = json.NewEncoder(w).Encode(agg.Snapshot())
+func NewAggregator(opts AggregatorOptions) *Aggregator {
+	if opts.Window <= 0 {
+		opts.Window = defaultAggregatorWindow
+	}
+	if opts.Samples <= 0 {
+		opts.Samples = defaultAggregatorSamples
+	}
+	opts.Clock = clockOrSystem(opts.Clock)
+
+	return &Aggregator{
+		opts:        opts,
+		bucketWidth: max(opts.Window/aggregatorBuckets, 1),
+		groups:      make(map[string]*errorGroup),
+	}
+}
+
+// Add records an occurrence of err and returns its fingerprint.
+// Groups without occurrences within the window are forgotten, so a group seen
+// again after that starts afresh. A nil err is ignored and returns the empty string.
+func (a *Aggregator) Add(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	fingerprint := Fingerprint(err)
+	msg := err.Error()
+	now := a.opts.Clock.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if now.Sub(a.pruned) >= a.bucketWidth {
+		a.prune(now)
+	}
+
+	group, ok := a.groups[fingerprint]
+	if ok {
+		a.expire(group, now)
+	}
+	if !ok || group.Count == 0 {
+		group = &errorGroup{ErrorGroup: ErrorGroup{
+			Fingerprint: fingerprint,
+			Type:        typeName(err),
+			Template:    MessageTemplate(msg),
+			FirstSeen:   now,
+		}}
+		a.groups[fingerprint] = group
+	}
+	group.LastSeen = now
+
+	start := now.Truncate(a.bucketWidth)
+	if n := len(group.buckets); n > 0 && group.buckets[n-1].start.Equal(start) {
+		group.buckets[n-1].count++
+	} else {
+		group.buckets = append(group.buckets, bucket{start: start, count: 1})
+	}
+	a.expire(group, now)
+
+	group.Samples = slices.DeleteFunc(group.Samples, func(s string) bool { return s == msg })
+	group.Samples = slices.Insert(group.Samples, 0, msg)
+	if len(group.Samples) > a.opts.Samples {
+		group.Samples = group.Samples[:a.opts.Samples]
+	}
+	return fingerprint
+}
+
+// expire drops the buckets of group that are entirely outside the window ending
+// at now, and updates its count.
+func (a *Aggregator) expire(group *errorGroup, now time.Time) {
+	cutoff := now.Add(-a.opts.Window)
+	i := 0
+	for i < len(group.buckets) && !group.buckets[i].start.Add(a.bucketWidth).After(cutoff) {
+		i++
+	}
+	group.buckets = group.buckets[i:]
+
+	group.Count = 0
+	for _, b := range group.buckets {
+		group.Count += b.count
+	}
+}
+
+// prune expires every group and forgets those without occurrences within the
+// window ending at now.
+func (a *Aggregator) prune(now time.Time) {
+	for fingerprint, group := range a.groups {
+		a.expire(group, now)
+		if group.Count == 0 {
+			delete(a.groups, fingerprint)
+		}
+	}
+	a.pruned = now
+}
+
+// Snapshot returns the groups with occurrences within the window, most frequent
+// first, and forgets the others. Counts are accurate to a sixtieth of the window.
+func (a *Aggregator) Snapshot() []ErrorGroup {
+	now := a.opts.Clock.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.prune(now)
+	groups := make([]ErrorGroup, 0, len(a.groups))
+	for _, group := range a.groups {
+		snapshot := group.ErrorGroup
+		snapshot.Samples = slices.Clone(group.Samples)
+		groups = append(groups, snapshot)
+	}
+
+	slices.SortFunc(groups, func(x, y ErrorGroup) int {
+		return cmp.Or(
+			cmp.Compare(y.Count, x.Count),
+			strings.Compare(x.Fingerprint, y.Fingerprint),
+		)
+	})
+	return groups
+}
@@ -0,0 +1,217 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Sentinels with the same message and type that only differ by their code.
+var (
+	errFingerprintA = Register("test.fingerprint.a", New("same message"))
+	errFingerprintB = Register("test.fingerprint.b", New("same message"))
+)
+
+func TestMessageTemplate(t *testing.T) {
+	tests := []struct {
+		msg, want string
+	}{
+		{"user 42 not found", "user <n> not found"},
+		{"took 1.5s", "took <n>s"},
+		{"dial tcp 10.0.0.7:5432: connection refused", "dial tcp <ip>: connection refused"},
+		{"dial tcp [::1]:5432: connection refused", "dial tcp <ip>: connection refused"},
+		{"order 3f2504e0-4f89-11d3-9a0c-0305e82c3301 failed", "order <uuid> failed"},
+		{"object at 0xc000012345", "object at <hex>"},
+		{"commit 9fceb02d0ae598e95dc970b74767f19372d61af8 missing", "commit <hex> missing"},
+		{"decoded feedface", "decoded feedface"},
+		{"connection reset by peer", "connection reset by peer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.Equal(t, tt.want, MessageTemplate(tt.msg))
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Run("Fingerprint with nil error", func(t *testing.T) {
+		assert.Empty(t, Fingerprint(nil))
+	})
+
+	t.Run("Fingerprint is short and stable", func(t *testing.T) {
+		fp := Fingerprint(io.EOF)
+		assert.Len(t, fp, 16)
+		assert.Equal(t, fp, Fingerprint(io.EOF))
+	})
+
+	t.Run("Fingerprint ignores variable parts", func(t *testing.T) {
+		assert.Equal(t,
+			Fingerprint(fmt.Errorf("load user %d from 10.0.0.%d: %w", 42, 1, ErrSentinel1)),
+			Fingerprint(fmt.Errorf("load user %d from 10.0.0.%d: %w", 7, 2, ErrSentinel1)),
+		)
+	})
+
+	t.Run("Fingerprint depends on the message template", func(t *testing.T) {
+		assert.NotEqual(t,
+			Fingerprint(fmt.Errorf("load user: %w", io.EOF)),
+			Fingerprint(fmt.Errorf("save user: %w", io.EOF)),
+		)
+	})
+
+	t.Run("Fingerprint depends on types", func(t *testing.T) {
+		assert.NotEqual(t,
+			Fingerprint(&ValidationError{Field: "a", Value: "b"}),
+			Fingerprint(fmt.Errorf("validation failed for field a with value b")),
+		)
+	})
+
+	t.Run("Fingerprint depends on sentinel codes", func(t *testing.T) {
+		assert.NotEqual(t, Fingerprint(errFingerprintA), Fingerprint(errFingerprintB))
+	})
+
+	t.Run("Fingerprint survives serialization", func(t *testing.T) {
+		err := With(Wrap(WithKind(ErrSentinel1, NotFound), "load user 42"), "user_id", 42)
+
+		data, jsonErr := json.Marshal(Serialize(err))
+		require.NoError(t, jsonErr)
+		var s SerializedError
+		require.NoError(t, json.Unmarshal(data, &s))
+
+		assert.Equal(t, Fingerprint(err), Fingerprint(s.Err()))
+	})
+}
+
+func TestAggregator(t *testing.T) {
+	t.Run("Aggregator groups similar errors", func(t *testing.T) {
+		agg := NewAggregator(AggregatorOptions{Clock: newFakeClock()})
+		fp := agg.Add(fmt.Errorf("load user 1: %w", io.EOF))
+		agg.Add(fmt.Errorf("load user 2: %w", io.EOF))
+		agg.Add(fmt.Errorf("load user 1: %w", io.EOF))
+		other := agg.Add(io.ErrUnexpectedEOF)
+		assert.Empty(t, agg.Add(nil))
+
+		snapshot := agg.Snapshot()
+		require.Len(t, snapshot, 2)
+
+		assert.Equal(t, fp, snapshot[0].Fingerprint)
+		assert.Equal(t, "*fmt.wrapError", snapshot[0].Type)
+		assert.Equal(t, "load user <n>: EOF", snapshot[0].Template)
+		assert.Equal(t, 3, snapshot[0].Count)
+		assert.Equal(t, []string{"load user 1: EOF", "load user 2: EOF"}, snapshot[0].Samples)
+
+		assert.Equal(t, other, snapshot[1].Fingerprint)
+		assert.Equal(t, 1, snapshot[1].Count)
+	})
+
+	t.Run("Aggregator keeps the most recent samples", func(t *testing.T) {
+		agg := NewAggregator(AggregatorOptions{Samples: 2, Clock: newFakeClock()})
+		for i := range 5 {
+			agg.Add(fmt.Errorf("item %d: %w", i, io.EOF))
+		}
+
+		snapshot := agg.Snapshot()
+		require.Len(t, snapshot, 1)
+		assert.Equal(t, []string{"item 4: EOF", "item 3: EOF"}, snapshot[0].Samples)
+	})
+
+	t.Run("Aggregator sliding window", func(t *testing.T) {
+		clock := newFakeClock()
+		agg := NewAggregator(AggregatorOptions{Window: time.Minute, Clock: clock})
+		start := clock.Now()
+
+		agg.Add(io.EOF)
+		clock.Advance(30 * time.Second)
+		agg.Add(io.EOF)
+		agg.Add(io.ErrUnexpectedEOF)
+
+		snapshot := agg.Snapshot()
+		require.Len(t, snapshot, 2)
+		assert.Equal(t, 2, snapshot[0].Count)
+		assert.Equal(t, start, snapshot[0].FirstSeen)
+		assert.Equal(t, start.Add(30*time.Second), snapshot[0].LastSeen)
+
+		clock.Advance(45 * time.Second)
+		snapshot = agg.Snapshot()
+		require.Len(t, snapshot, 2)
+		assert.Equal(t, 1, snapshot[0].Count)
+		assert.Equal(t, 1, snapshot[1].Count)
+
+		clock.Advance(time.Minute)
+		assert.Empty(t, agg.Snapshot())
+
+		agg.Add(io.EOF)
+		snapshot = agg.Snapshot()
+		require.Len(t, snapshot, 1)
+		assert.Equal(t, clock.Now(), snapshot[0].FirstSeen)
+	})
+
+	t.Run("Aggregator forgets expired groups without snapshots", func(t *testing.T) {
+		clock := newFakeClock()
+		agg := NewAggregator(AggregatorOptions{Window: time.Minute, Clock: clock})
+
+		agg.Add(io.EOF)
+		for i := range 10 {
+			clock.Advance(2 * time.Minute)
+			agg.Add(fmt.Errorf("item %d: %w", i, io.ErrUnexpectedEOF))
+		}
+		agg.mu.Lock()
+		assert.Len(t, agg.groups, 1)
+		agg.mu.Unlock()
+
+		agg.Add(io.EOF)
+		snapshot := agg.Snapshot()
+		require.Len(t, snapshot, 2)
+		for _, group := range snapshot {
+			assert.Equal(t, 1, group.Count)
+			assert.Equal(t, clock.Now(), group.FirstSeen)
+		}
+	})
+
+	t.Run("Aggregator snapshot is a copy", func(t *testing.T) {
+		agg := NewAggregator(AggregatorOptions{Clock: newFakeClock()})
+		agg.Add(io.EOF)
+		snapshot := agg.Snapshot()
+		snapshot[0].Samples[0] = "changed"
+
+		assert.Equal(t, []string{"EOF"}, agg.Snapshot()[0].Samples)
+	})
+
+	t.Run("Aggregator snapshot JSON", func(t *testing.T) {
+		agg := NewAggregator(AggregatorOptions{Clock: newFakeClock()})
+		fp := agg.Add(io.EOF)
+
+		data, err := json.Marshal(agg.Snapshot())
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{
+			"fingerprint": "`+fp+`",
+			"type": "*errors.errorString",
+			"template": "EOF",
+			"count": 1,
+			"firstSeen": "2025-01-01T00:00:00Z",
+			"lastSeen": "2025-01-01T00:00:00Z",
+			"samples": ["EOF"]
+		}]`, string(data))
+	})
+
+	t.Run("Aggregator concurrent use", func(t *testing.T) {
+		agg := NewAggregator(AggregatorOptions{})
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Go(func() {
+				agg.Add(fmt.Errorf("request %d: %w", i, io.EOF))
+				_ = agg.Snapshot()
+			})
+		}
+		wg.Wait()
+
+		snapshot := agg.Snapshot()
+		require.Len(t, snapshot, 1)
+		assert.Equal(t, 50, snapshot[0].Count)
+	})
+}
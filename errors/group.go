@@ -0,0 +1,156 @@
+package errors
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// TaskError is an error returned by a task of a Group, tagged with the task index.
+type TaskError struct {
+	Index int // Order in which the task was started with Group.Go, from 0
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// GroupOptions configures a Group.
+type GroupOptions struct {
+	// Limit is the maximum number of tasks running at once.
+	// Zero or a negative value means no limit.
+	Limit int
+	// Matchers handle each task error as it arrives, as Handle would.
+	// A handled error is replaced by the handler's result, which is dropped if nil.
+	Matchers []ErrorMatcher
+	// CancelOnUnhandled cancels the group's context as soon as a task returns an
+	// error that no matcher handles. Otherwise the remaining tasks keep running.
+	CancelOnUnhandled bool
+}
+
+// Group runs tasks concurrently and collects all of their errors.
+// Unlike a plain error group, it keeps every error rather than only the first,
+// and routes each of them through a set of matchers as it arrives.
+//
+// A Group must be created with NewGroup and must not be copied.
+type Group struct {
+	opts   GroupOptions
+	cancel context.CancelCauseFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	next int
+	errs []*TaskError
+}
+
+// NewGroup returns a new Group and a context derived from ctx.
+// The derived context is canceled when a task returns an unhandled error and
+// opts.CancelOnUnhandled is set, or when Wait returns, whichever occurs first.
+//
+// Example:
+//
+//	g, ctx := errors.NewGroup(ctx, errors.GroupOptions{
+//	    Limit: 8,
+//	    Matchers: []errors.ErrorMatcher{
+//	        errors.OnSentinel(ErrAlreadyProcessed, func(e error) error {
+//	            return nil // safe to ignore
+//	        }),
+//	    },
+//	    CancelOnUnhandled: true,
+//	})
+//	for _, item := range items {
+//	    g.Go(func() error {
+//	        return process(ctx, item)
+//	    })
+//	}
+//	err := g.Wait() // joined *TaskError values, or nil
+func NewGroup(ctx context.Context, opts GroupOptions) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{
+		opts:   opts,
+		cancel: cancel,
+	}
+	if opts.Limit > 0 {
+		g.sem = make(chan struct{}, opts.Limit)
+	}
+	return g, ctx
+}
+
+// Go runs fn in a new goroutine.
+// If the group has a limit, Go blocks until fn can start without exceeding it.
+// A panic in fn is recovered and reported as a *PanicError.
+//
+// The task's error, if any, is passed through the group's matchers. Handlers are
+// never called concurrently with each other, so they may share state freely.
+func (g *Group) Go(fn func() error) {
+	g.mu.Lock()
+	index := g.next
+	g.next++
+	g.mu.Unlock()
+
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Go(func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+		}()
+
+		err := func() (err error) {
+			defer Recover(&err)
+			return fn()
+		}()
+		if err != nil {
+			g.collect(index, err)
+		}
+	})
+}
+
+// collect routes a task error through the group's matchers and records the outcome.
+func (g *Group) collect(index int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	handled, result := HandleError(err, g.opts.Matchers...)
+	if handled && result == nil {
+		return
+	}
+
+	taskErr := &TaskError{Index: index, Err: result}
+	g.errs = append(g.errs, taskErr)
+	if !handled && g.opts.CancelOnUnhandled {
+		g.cancel(taskErr)
+	}
+}
+
+// Wait blocks until all tasks have returned, cancels the group's context, and
+// returns the collected errors joined with errors.Join, ordered by task index.
+// Each joined error is a *TaskError. Wait returns nil if no error remains.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(nil)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	slices.SortFunc(g.errs, func(a, b *TaskError) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+	errs := make([]error, len(g.errs))
+	for i, taskErr := range g.errs {
+		errs[i] = taskErr
+	}
+	return errors.Join(errs...)
+}
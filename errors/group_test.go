@@ -0,0 +1,177 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taskErrors unpacks the *TaskError values joined by Group.Wait.
+func taskErrors(t *testing.T, err error) []*TaskError {
+	t.Helper()
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok, "error is not a joined error: %v", err)
+
+	result := make([]*TaskError, 0, len(joined.Unwrap()))
+	for _, e := range joined.Unwrap() {
+		var taskErr *TaskError
+		require.ErrorAs(t, e, &taskErr)
+		result = append(result, taskErr)
+	}
+	return result
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("Group with no errors", func(t *testing.T) {
+		g, _ := NewGroup(t.Context(), GroupOptions{})
+		for range 5 {
+			g.Go(func() error { return nil })
+		}
+		assert.NoError(t, g.Wait())
+	})
+
+	t.Run("Group collects every error in task order", func(t *testing.T) {
+		g, _ := NewGroup(t.Context(), GroupOptions{})
+		for i := range 5 {
+			g.Go(func() error {
+				// Finish in reverse order to exercise sorting
+				time.Sleep(time.Duration(5-i) * time.Millisecond)
+				if i%2 == 0 {
+					return fmt.Errorf("failure %d", i)
+				}
+				return nil
+			})
+		}
+
+		err := g.Wait()
+		taskErrs := taskErrors(t, err)
+		require.Len(t, taskErrs, 3)
+		for i, taskErr := range taskErrs {
+			assert.Equal(t, i*2, taskErr.Index)
+			assert.EqualError(t, taskErr, fmt.Sprintf("task %d: failure %d", i*2, i*2))
+		}
+	})
+
+	t.Run("Group respects limit", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		g, _ := NewGroup(t.Context(), GroupOptions{Limit: 2})
+		for range 10 {
+			g.Go(func() error {
+				current := running.Add(1)
+				for {
+					observed := maxRunning.Load()
+					if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+						break
+					}
+				}
+				time.Sleep(2 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			})
+		}
+
+		require.NoError(t, g.Wait())
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+		assert.Positive(t, maxRunning.Load())
+	})
+
+	t.Run("Group routes errors through matchers", func(t *testing.T) {
+		var handledCount int
+		g, _ := NewGroup(t.Context(), GroupOptions{
+			Matchers: []ErrorMatcher{
+				OnSentinel(io.EOF, func(e error) error {
+					handledCount++
+					return nil
+				}),
+				OnType(func(e *ValidationError) error {
+					handledCount++
+					return fmt.Errorf("invalid %s", e.Field)
+				}),
+			},
+		})
+		g.Go(func() error { return io.EOF })
+		g.Go(func() error { return &ValidationError{Field: "email"} })
+		g.Go(func() error { return io.ErrClosedPipe })
+		g.Go(func() error { return fmt.Errorf("wrapped: %w", io.EOF) })
+
+		err := g.Wait()
+		taskErrs := taskErrors(t, err)
+		require.Len(t, taskErrs, 2)
+		assert.Equal(t, 1, taskErrs[0].Index)
+		require.EqualError(t, taskErrs[0].Err, "invalid email")
+		assert.Equal(t, 2, taskErrs[1].Index)
+		require.ErrorIs(t, taskErrs[1], io.ErrClosedPipe)
+		assert.Equal(t, 3, handledCount)
+	})
+
+	t.Run("Group cancels on first unhandled error", func(t *testing.T) {
+		g, ctx := NewGroup(t.Context(), GroupOptions{
+			CancelOnUnhandled: true,
+			Matchers: []ErrorMatcher{
+				OnSentinel(io.EOF, func(e error) error { return nil }),
+			},
+		})
+		g.Go(func() error { return io.EOF })
+		g.Go(func() error { return io.ErrClosedPipe })
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		err := g.Wait()
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+		require.ErrorIs(t, err, context.Canceled)
+
+		var taskErr *TaskError
+		require.ErrorAs(t, context.Cause(ctx), &taskErr)
+		assert.Equal(t, 1, taskErr.Index)
+	})
+
+	t.Run("Group keeps going without CancelOnUnhandled", func(t *testing.T) {
+		// With a limit of 1, the second Go returns only once the first task
+		// has finished and its error has been collected.
+		g, ctx := NewGroup(t.Context(), GroupOptions{Limit: 1})
+		g.Go(func() error { return io.ErrClosedPipe })
+
+		live := make(chan error, 1)
+		g.Go(func() error {
+			live <- ctx.Err()
+			return nil
+		})
+		require.NoError(t, ctx.Err())
+
+		err := g.Wait()
+		require.NoError(t, <-live)
+		assert.Len(t, taskErrors(t, err), 1)
+		require.Error(t, ctx.Err())
+	})
+
+	t.Run("Group recovers panics", func(t *testing.T) {
+		g, _ := NewGroup(t.Context(), GroupOptions{})
+		g.Go(func() error { panic("task crashed") })
+
+		err := g.Wait()
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "task crashed", panicErr.Value)
+	})
+
+	t.Run("Group context inherits parent cancellation", func(t *testing.T) {
+		parent, cancel := context.WithCancel(t.Context())
+		g, ctx := NewGroup(parent, GroupOptions{})
+		cancel()
+
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.ErrorIs(t, g.Wait(), context.Canceled)
+	})
+}
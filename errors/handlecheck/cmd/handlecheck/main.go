@@ -0,0 +1,15 @@
+// Command handlecheck runs the handlecheck analyzer.
+//
+// Usage:
+//
+//	go run go.aykhans.me/utils/errors/handlecheck/cmd/handlecheck ./...
+package main
+
+import (
+	"go.aykhans.me/utils/errors/handlecheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(handlecheck.Analyzer)
+}
@@ -0,0 +1,392 @@
+// Package handlecheck defines an analyzer that reports calls to Handle and
+// MustHandle from go.aykhans.me/utils/errors that leave out an error the called
+// function declares it returns.
+//
+// A function declares its errors with an errors:returns directive in its doc
+// comment, listing sentinel variables and error types separated by spaces.
+// Names are resolved like identifiers in the function's file, so they may be
+// qualified with an imported package name:
+//
+//	// Load reads the user with the given ID.
+//	//
+//	//errors:returns ErrNotFound ErrTimeout *ValidationError io.EOF
+//	func Load(id int) (*User, error)
+//
+// The analyzer then checks calls such as
+//
+//	u, err := Load(id)
+//	errors.MustHandle(err,
+//	    errors.OnSentinel(ErrNotFound, notFound),
+//	    errors.OnType(invalid),
+//	)
+//
+// and reports that ErrTimeout and io.EOF are not handled. The handled error may
+// be a variable assigned from the call earlier in the same function, or the
+// call itself for functions that only return an error. Declarations are exported as facts, so functions from other packages
+// are checked as well.
+//
+// Only matchers built with OnSentinel, OnSentinels and OnType (or their long
+// names) are understood. Calls that use any other matcher, or pass the matchers
+// as a slice, are skipped, since such matchers may handle anything.
+package handlecheck
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// errorsPath is the import path of the errors package whose calls are checked.
+const errorsPath = "go.aykhans.me/utils/errors"
+
+// directive is the doc comment prefix that declares the errors a function returns.
+const directive = "//errors:returns "
+
+// Analyzer reports Handle and MustHandle calls that do not handle every declared error.
+var Analyzer = &analysis.Analyzer{
+	Name:      "handlecheck",
+	Doc:       "report Handle and MustHandle calls that leave out errors declared with errors:returns",
+	URL:       "https://pkg.go.dev/go.aykhans.me/utils/errors/handlecheck",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(declaredErrors)},
+}
+
+// handleFuncs are the functions whose calls are checked.
+var handleFuncs = []string{"Handle", "HandleError", "MustHandle", "HandleErrorOrDie"}
+
+// declaredErrors is the fact exported for functions with an errors:returns directive.
+type declaredErrors struct {
+	// Names are the errors as written in the directive, used in reports.
+	Names []string
+	// IDs identify the errors: "path.Name" for sentinels, the type string for types.
+	IDs []string
+}
+
+func (*declaredErrors) AFact() {}
+
+func (f *declaredErrors) String() string {
+	return "returns " + strings.Join(f.Names, " ")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		exportDeclarations(pass, file)
+	}
+	for _, file := range pass.Files {
+		assigns := collectAssignments(pass, file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				checkCall(pass, call, assigns)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// exportDeclarations exports a declaredErrors fact for every function in file
+// with an errors:returns directive.
+func exportDeclarations(pass *analysis.Pass, file *ast.File) {
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Doc == nil {
+			continue
+		}
+		fn, ok := pass.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+
+		fact := new(declaredErrors)
+		for _, comment := range funcDecl.Doc.List {
+			list, ok := strings.CutPrefix(comment.Text, directive)
+			if !ok {
+				continue
+			}
+			list, _, _ = strings.Cut(list, "//") // allow a trailing comment
+			for name := range strings.FieldsSeq(list) {
+				id, ok := resolve(pass, file, name)
+				if !ok {
+					pass.Reportf(comment.Pos(), "errors:returns: cannot resolve %s", name)
+					continue
+				}
+				fact.Names = append(fact.Names, name)
+				fact.IDs = append(fact.IDs, id)
+			}
+		}
+		if len(fact.IDs) > 0 {
+			pass.ExportObjectFact(fn, fact)
+		}
+	}
+}
+
+// resolve returns the identity of an error named in a directive of file:
+// a package-level variable or constant, a type, or a pointer to a type, optionally
+// qualified with the name of a package imported by file.
+func resolve(pass *analysis.Pass, file *ast.File, name string) (string, bool) {
+	ident, pointer := strings.CutPrefix(name, "*")
+
+	scope := pass.Pkg.Scope()
+	if qualifier, sel, ok := strings.Cut(ident, "."); ok {
+		pkg := importedPackage(pass, file, qualifier)
+		if pkg == nil {
+			return "", false
+		}
+		scope, ident = pkg.Scope(), sel
+	}
+
+	switch obj := scope.Lookup(ident).(type) {
+	case *types.Var, *types.Const:
+		if pointer {
+			return "", false
+		}
+		return sentinelID(obj), true
+	case *types.TypeName:
+		typ := obj.Type()
+		if pointer {
+			typ = types.NewPointer(typ)
+		}
+		return types.TypeString(typ, nil), true
+	default:
+		return "", false
+	}
+}
+
+// importedPackage returns the package imported by file under the given name, or nil.
+func importedPackage(pass *analysis.Pass, file *ast.File, name string) *types.Package {
+	for _, spec := range file.Imports {
+		if obj := pass.TypesInfo.PkgNameOf(spec); obj != nil && obj.Name() == name {
+			return obj.Imported()
+		}
+	}
+	return nil
+}
+
+// sentinelID returns the identity of a package-level sentinel variable or constant.
+func sentinelID(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// assignment records a value assigned to a variable: the call it came from,
+// or nil if it was not assigned from a call.
+type assignment struct {
+	pos  token.Pos
+	call *ast.CallExpr
+}
+
+// collectAssignments records, for every variable in file, the values assigned to it.
+func collectAssignments(pass *analysis.Pass, file *ast.File) map[types.Object][]assignment {
+	assigns := make(map[types.Object][]assignment)
+	record := func(lhs []*ast.Ident, rhs []ast.Expr, pos token.Pos) {
+		for i, ident := range lhs {
+			obj := pass.TypesInfo.ObjectOf(ident)
+			if obj == nil {
+				continue
+			}
+			var value ast.Expr
+			switch len(rhs) {
+			case 1:
+				value = rhs[0]
+			case len(lhs):
+				value = rhs[i]
+			}
+			call, _ := ast.Unparen(value).(*ast.CallExpr)
+			assigns[obj] = append(assigns[obj], assignment{pos: pos, call: call})
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			var lhs []*ast.Ident
+			for _, expr := range stmt.Lhs {
+				if ident, ok := expr.(*ast.Ident); ok {
+					lhs = append(lhs, ident)
+				}
+			}
+			record(lhs, stmt.Rhs, stmt.Pos())
+		case *ast.ValueSpec:
+			record(stmt.Names, stmt.Values, stmt.Pos())
+		}
+		return true
+	})
+	return assigns
+}
+
+// checkCall reports the declared errors that call leaves out, if call is a
+// call to one of handleFuncs.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, assigns map[types.Object][]assignment) {
+	name, ok := errorsFunc(pass, call)
+	if !ok || !slices.Contains(handleFuncs, name) || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return
+	}
+
+	source := sourceCall(pass, call.Args[0], call.Pos(), assigns)
+	if source == nil {
+		return
+	}
+	fn, ok := calledObject(pass, source).(*types.Func)
+	if !ok {
+		return
+	}
+	var fact declaredErrors
+	if !pass.ImportObjectFact(fn, &fact) {
+		return
+	}
+
+	handled, ok := handledErrors(pass, call.Args[1:])
+	if !ok {
+		return
+	}
+
+	var missing []string
+	for i, id := range fact.IDs {
+		if !handled[id] {
+			missing = append(missing, fact.Names[i])
+		}
+	}
+	if len(missing) > 0 {
+		pass.Reportf(call.Pos(), "%s does not handle %s returned by %s",
+			name, strings.Join(missing, ", "), fn.Name())
+	}
+}
+
+// sourceCall returns the call that produced the handled error: the expression
+// itself, or the last call assigned to the variable before pos.
+func sourceCall(
+	pass *analysis.Pass,
+	expr ast.Expr,
+	pos token.Pos,
+	assigns map[types.Object][]assignment,
+) *ast.CallExpr {
+	switch x := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		return x
+	case *ast.Ident:
+		var last assignment
+		for _, a := range assigns[pass.TypesInfo.ObjectOf(x)] {
+			if a.pos < pos && a.pos > last.pos {
+				last = a
+			}
+		}
+		return last.call
+	default:
+		return nil
+	}
+}
+
+// handledErrors returns the identities of the errors the matcher expressions
+// handle. It reports false if any matcher is not understood.
+func handledErrors(pass *analysis.Pass, matchers []ast.Expr) (map[string]bool, bool) {
+	handled := make(map[string]bool)
+	for _, expr := range matchers {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return nil, false
+		}
+		name, ok := errorsFunc(pass, call)
+		if !ok {
+			return nil, false
+		}
+
+		switch name {
+		case "OnSentinel", "OnSentinelError":
+			id, ok := sentinelOf(pass, call.Args[0])
+			if !ok {
+				return nil, false
+			}
+			handled[id] = true
+		case "OnSentinels":
+			list, ok := ast.Unparen(call.Args[0]).(*ast.CompositeLit)
+			if !ok {
+				return nil, false
+			}
+			for _, elt := range list.Elts {
+				id, ok := sentinelOf(pass, elt)
+				if !ok {
+					return nil, false
+				}
+				handled[id] = true
+			}
+		case "OnType", "OnCustomError":
+			inst, ok := pass.TypesInfo.Instances[funcIdent(call)]
+			if !ok || inst.TypeArgs.Len() != 1 {
+				return nil, false
+			}
+			handled[types.TypeString(inst.TypeArgs.At(0), nil)] = true
+		default:
+			return nil, false
+		}
+	}
+	return handled, true
+}
+
+// sentinelOf returns the identity of the package-level variable or constant
+// expr refers to.
+func sentinelOf(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	var ident *ast.Ident
+	switch x := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = x
+	case *ast.SelectorExpr:
+		ident = x.Sel
+	default:
+		return "", false
+	}
+	obj := pass.TypesInfo.Uses[ident]
+	switch obj.(type) {
+	case *types.Var, *types.Const:
+	default:
+		return "", false
+	}
+	if obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+		return "", false
+	}
+	return sentinelID(obj), true
+}
+
+// errorsFunc returns the name of the function or variable from the errors
+// package that call calls, if any.
+func errorsFunc(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	obj := calledObject(pass, call)
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != errorsPath {
+		return "", false
+	}
+	return obj.Name(), true
+}
+
+// calledObject returns the object of the function or variable call calls, or nil.
+func calledObject(pass *analysis.Pass, call *ast.CallExpr) types.Object {
+	ident := funcIdent(call)
+	if ident == nil {
+		return nil
+	}
+	return pass.TypesInfo.Uses[ident]
+}
+
+// funcIdent returns the identifier naming the function call calls, or nil.
+func funcIdent(call *ast.CallExpr) *ast.Ident {
+	fun := ast.Unparen(call.Fun)
+	switch x := fun.(type) {
+	case *ast.IndexExpr:
+		fun = x.X
+	case *ast.IndexListExpr:
+		fun = x.X
+	}
+
+	switch x := fun.(type) {
+	case *ast.Ident:
+		return x
+	case *ast.SelectorExpr:
+		return x.Sel
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,12 @@
+package handlecheck_test
+
+import (
+	"testing"
+
+	"go.aykhans.me/utils/errors/handlecheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), handlecheck.Analyzer, "a", "b")
+}
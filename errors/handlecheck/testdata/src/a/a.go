@@ -0,0 +1,114 @@
+package a
+
+import (
+	"errors"
+	"io"
+
+	"b"
+
+	uerrors "go.aykhans.me/utils/errors"
+)
+
+var (
+	ErrA = errors.New("a")
+	ErrB = errors.New("b")
+)
+
+const ErrC = uerrors.Const("c")
+
+type ValidationError struct{}
+
+func (*ValidationError) Error() string { return "invalid" }
+
+// Load loads something.
+//
+//errors:returns ErrA ErrB *ValidationError
+func Load() error { return nil } // want Load:`returns ErrA ErrB \*ValidationError`
+
+func handled(error) error { return nil }
+
+func direct() {
+	uerrors.MustHandle(Load(), // want `MustHandle does not handle ErrB, \*ValidationError returned by Load`
+		uerrors.OnSentinel(ErrA, handled),
+	)
+
+	uerrors.MustHandle(Load(),
+		uerrors.OnSentinel(ErrA, handled),
+		uerrors.OnSentinelError(ErrB, handled),
+		uerrors.OnType(func(*ValidationError) error { return nil }),
+	)
+
+	uerrors.Handle(Load(), // want `Handle does not handle \*ValidationError returned by Load`
+		uerrors.OnSentinels([]error{ErrA, ErrB}, handled),
+	)
+}
+
+func viaVariable() {
+	err := Load()
+	uerrors.MustHandle(err, // want `MustHandle does not handle ErrA, ErrB returned by Load`
+		uerrors.OnCustomError(func(*ValidationError) error { return nil }),
+	)
+
+	if err := b.Read(); err != nil {
+		uerrors.HandleErrorOrDie(err, // want `HandleErrorOrDie does not handle io.EOF returned by Read`
+			uerrors.OnType(func(*b.NotFoundError) error { return nil }),
+		)
+	}
+
+	err = b.Read()
+	uerrors.MustHandle(err,
+		uerrors.OnType(func(*b.NotFoundError) error { return nil }),
+		uerrors.OnSentinel(io.EOF, handled),
+	)
+}
+
+type User struct{}
+
+// Find finds a user.
+//
+//errors:returns ErrA ErrB
+func Find() (*User, error) { return nil, nil } // want Find:`returns ErrA ErrB`
+
+func viaTuple() {
+	u, err := Find()
+	_ = u
+	uerrors.MustHandle(err, // want `MustHandle does not handle ErrB returned by Find`
+		uerrors.OnSentinel(ErrA, handled),
+	)
+}
+
+// Fetch fetches something.
+//
+//errors:returns ErrA ErrC
+func Fetch() error { return nil } // want Fetch:`returns ErrA ErrC`
+
+func constants() {
+	uerrors.MustHandle(Fetch(), // want `MustHandle does not handle ErrA returned by Fetch`
+		uerrors.OnSentinel(ErrC, handled),
+	)
+
+	uerrors.MustHandle(Fetch(),
+		uerrors.OnSentinels([]error{ErrA, ErrC}, handled),
+	)
+
+	uerrors.MustHandle(b.Close()) // want `MustHandle does not handle ErrClosed returned by Close`
+
+	uerrors.MustHandle(b.Close(),
+		uerrors.OnSentinel(b.ErrClosed, handled),
+	)
+}
+
+func skipped(matchers []uerrors.ErrorMatcher) {
+	uerrors.MustHandle(Load(), matchers...)
+
+	uerrors.MustHandle(Load(),
+		uerrors.OnSentinel(ErrA, handled),
+		uerrors.On(func(error) bool { return true }, handled),
+	)
+
+	uerrors.MustHandle(errors.New("undeclared"))
+
+	err := b.Broken()
+	err = errors.New("reassigned")
+	uerrors.MustHandle(err)
+}
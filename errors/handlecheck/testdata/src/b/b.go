@@ -0,0 +1,26 @@
+package b
+
+import (
+	"io"
+
+	uerrors "go.aykhans.me/utils/errors"
+)
+
+const ErrClosed = uerrors.Const("closed")
+
+type NotFoundError struct{}
+
+func (*NotFoundError) Error() string { return "not found" }
+
+// Read reads from the store.
+//
+//errors:returns *NotFoundError io.EOF
+func Read() error { return io.EOF } // want Read:`returns \*NotFoundError io.EOF`
+
+//errors:returns ErrMissing // want `errors:returns: cannot resolve ErrMissing`
+func Broken() error { return nil }
+
+// Close closes the store.
+//
+//errors:returns ErrClosed
+func Close() error { return ErrClosed } // want Close:`returns ErrClosed`
@@ -0,0 +1,30 @@
+// Package errors is a stub of go.aykhans.me/utils/errors for the analyzer tests.
+package errors
+
+type ErrorHandler func(error) error
+
+type ErrorMatcher struct{}
+
+func HandleError(err error, matchers ...ErrorMatcher) (bool, error) { return false, err }
+
+var Handle = HandleError
+
+func HandleErrorOrDie(err error, matchers ...ErrorMatcher) error { return err }
+
+var MustHandle = HandleErrorOrDie
+
+func OnSentinelError(sentinelErr error, handler ErrorHandler) ErrorMatcher { return ErrorMatcher{} }
+
+var OnSentinel = OnSentinelError
+
+func OnSentinels(sentinelErrs []error, handler ErrorHandler) ErrorMatcher { return ErrorMatcher{} }
+
+func OnCustomError[T error](handler func(T) error) ErrorMatcher { return ErrorMatcher{} }
+
+func OnType[T error](handler func(T) error) ErrorMatcher { return ErrorMatcher{} }
+
+func On(cond func(error) bool, handler ErrorHandler) ErrorMatcher { return ErrorMatcher{} }
+
+type Const string
+
+func (e Const) Error() string { return string(e) }
@@ -3,17 +3,59 @@ package errors
 import (
 	"errors"
 	"fmt"
-	"reflect"
+	"regexp"
 )
 
 // ErrorHandler represents a function that handles a specific error type
 type ErrorHandler func(error) error
 
-// ErrorMatcher holds the error type/value and its handler
+// ErrorMatcher holds the condition an error must satisfy and its handler
 type ErrorMatcher struct {
-	ErrorType  any // Can be error value (sentinel) or error type
-	Handler    ErrorHandler
-	IsSentinel bool // true for sentinel errors, false for custom types
+	Condition Condition
+	Handler   ErrorHandler
+
+	// Name identifies the matcher within a Handler, for Handler.Override and
+	// for middleware that report per-matcher metrics. It may be empty.
+	Name string
+	// Priority orders the matchers of a Handler: higher priorities are tried
+	// first, and matchers with equal priorities keep their order. Defaults to 0.
+	Priority int
+}
+
+// Match reports whether err satisfies the matcher's condition.
+// A matcher without a condition never matches.
+func (m ErrorMatcher) Match(err error) bool {
+	return m.Condition != nil && m.Condition(err)
+}
+
+// WithName returns a copy of the matcher with the given name.
+//
+// Example:
+//
+//	errors.OnSentinel(sql.ErrNoRows, notFound).WithName("db.not_found")
+func (m ErrorMatcher) WithName(name string) ErrorMatcher {
+	m.Name = name
+	return m
+}
+
+// WithPriority returns a copy of the matcher with the given priority.
+// Within a Handler, matchers with higher priorities are tried first.
+//
+// Example:
+//
+//	errors.OnType(rejectInvalid).WithPriority(10)
+func (m ErrorMatcher) WithPriority(priority int) ErrorMatcher {
+	m.Priority = priority
+	return m
+}
+
+// try runs the matcher's handler if err satisfies its condition.
+// It reports whether the matcher matched, along with the handler's result.
+func (m ErrorMatcher) try(err error) (bool, error) {
+	if m.Match(err) {
+		return true, m.Handler(err)
+	}
+	return false, nil
 }
 
 // HandleError processes an error against a list of matchers and executes the appropriate handler.
@@ -40,21 +82,8 @@ func HandleError(err error, matchers ...ErrorMatcher) (bool, error) {
 	}
 
 	for _, matcher := range matchers {
-		if matcher.IsSentinel {
-			// Handle sentinel errors with errors.Is
-			if sentinelErr, ok := matcher.ErrorType.(error); ok {
-				if errors.Is(err, sentinelErr) {
-					return true, matcher.Handler(err)
-				}
-			}
-		} else {
-			// Handle custom error types with errors.As
-			errorType := reflect.TypeOf(matcher.ErrorType)
-			errorValue := reflect.New(errorType).Interface()
-
-			if errors.As(err, errorValue) {
-				return true, matcher.Handler(err)
-			}
+		if ok, result := matcher.try(err); ok {
+			return true, result
 		}
 	}
 
@@ -80,7 +109,8 @@ var Handle = HandleError
 
 // HandleErrorOrDie processes an error against a list of matchers and executes the appropriate handler.
 // If a matching handler is found, it returns the handler's result.
-// If no matcher matches the error, it panics with a descriptive message.
+// If no matcher matches the error, it panics with an error that describes and
+// wraps the unhandled error, so that Recover keeps it reachable with errors.Is.
 // This function is useful when all expected error types must be handled explicitly.
 //
 // Deprecated: HandleErrorOrDie is deprecated and will be removed in a future release.
@@ -99,14 +129,15 @@ var Handle = HandleError
 func HandleErrorOrDie(err error, matchers ...ErrorMatcher) error {
 	ok, err := HandleError(err, matchers...)
 	if !ok {
-		panic(fmt.Sprintf("Unhandled error of type %T: %v", err, err))
+		panic(fmt.Errorf("unhandled error of type %T: %w", err, err))
 	}
 	return err
 }
 
 // MustHandle processes an error against a list of matchers and executes the appropriate handler.
 // If a matching handler is found, it returns the handler's result.
-// If no matcher matches the error, it panics with a descriptive message.
+// If no matcher matches the error, it panics with an error that describes and
+// wraps the unhandled error, so that Recover keeps it reachable with errors.Is.
 // This function is useful when all expected error types must be handled explicitly.
 //
 // Example:
@@ -193,6 +224,45 @@ func HandleErrorOrDefault(err error, dft ErrorHandler, matchers ...ErrorMatcher)
 //	) // Returns nil for unmatched errors
 var HandleOr = HandleErrorOrDefault
 
+// HandleAll processes every independent error inside err against a list of matchers.
+// Joined errors (errors.Join, or any error implementing Unwrap() []error) are split
+// into their leaves, and each leaf is handled by the first matching handler, exactly
+// as Handle would do for a single error. Wrappers around a joined error are looked
+// through, so fmt.Errorf("batch: %w", errors.Join(errA, errB)) yields errA and errB.
+//
+// It returns the leaves that no matcher matched, and the handler results joined
+// with errors.Join (nil handler results are dropped).
+// If err is nil, returns (nil, nil).
+//
+// Example:
+//
+//	unhandled, result := HandleAll(errors.Join(errA, errB, errC),
+//	    OnSentinel(ErrDuplicate, func(e error) error {
+//	        return nil // duplicates are fine in a batch import
+//	    }),
+//	    OnType(func(e *ValidationError) error {
+//	        return fmt.Errorf("row rejected: %w", e)
+//	    }),
+//	)
+//	for _, e := range unhandled {
+//	    log.Printf("unexpected batch error: %v", e)
+//	}
+func HandleAll(err error, matchers ...ErrorMatcher) ([]error, error) {
+	var unhandled, results []error
+	for _, leaf := range leaves(err) {
+		ok, result := HandleError(leaf, matchers...)
+		if !ok {
+			unhandled = append(unhandled, leaf)
+			continue
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return unhandled, errors.Join(results...)
+}
+
 // OnSentinelError creates an ErrorMatcher for sentinel errors.
 // Sentinel errors are predefined error values that are compared using errors.Is.
 //
@@ -214,9 +284,8 @@ var HandleOr = HandleErrorOrDefault
 //	})
 func OnSentinelError(sentinelErr error, handler ErrorHandler) ErrorMatcher {
 	return ErrorMatcher{
-		ErrorType:  sentinelErr,
-		Handler:    handler,
-		IsSentinel: true,
+		Condition: IsAny(sentinelErr),
+		Handler:   handler,
 	}
 }
 
@@ -240,11 +309,14 @@ var OnSentinel = OnSentinelError
 
 // OnCustomError creates an ErrorMatcher for custom error types.
 // Custom error types are struct types that implement the error interface,
-// and are matched using errors.As to unwrap error chains.
+// and are matched using errors.As semantics to unwrap error chains.
 //
 // The type parameter T specifies the error type to match. The handler function
 // receives the unwrapped typed error, allowing you to access type-specific fields
-// and methods.
+// and methods. The type check is resolved when the matcher is built, so handling
+// an error does not use reflection. The error's chain is searched twice for a
+// handled error, once by the Condition and once by the Handler, so that either
+// field can be replaced on its own; the second search does not allocate.
 //
 // This is particularly useful for handling errors with additional context or data,
 // such as validation errors, network errors, or domain-specific errors.
@@ -267,17 +339,14 @@ var OnSentinel = OnSentinelError
 //	    return fmt.Errorf("invalid input: %w", e)
 //	})
 func OnCustomError[T error](handler func(T) error) ErrorMatcher {
-	var zero T
 	return ErrorMatcher{
-		ErrorType: zero,
+		Condition: HasType[T](),
 		Handler: func(err error) error {
-			var typedErr T
-			if errors.As(err, &typedErr) {
+			if typedErr, ok := asType[T](err); ok {
 				return handler(typedErr)
 			}
 			return nil
 		},
-		IsSentinel: false,
 	}
 }
 
@@ -309,3 +378,55 @@ func OnCustomError[T error](handler func(T) error) ErrorMatcher {
 func OnType[T error](handler func(T) error) ErrorMatcher {
 	return OnCustomError(handler)
 }
+
+// On creates an ErrorMatcher for an arbitrary Condition.
+// Any func(error) bool can be passed as the condition, and conditions can be
+// composed with And, Or and Not.
+//
+// Example:
+//
+//	matcher := On(And(HasType[*net.OpError](), Not(IsAny(context.Canceled))), func(e error) error {
+//	    return fmt.Errorf("network failure: %w", e)
+//	})
+func On(cond Condition, handler ErrorHandler) ErrorMatcher {
+	return ErrorMatcher{
+		Condition: cond,
+		Handler:   handler,
+	}
+}
+
+// OnSentinels creates an ErrorMatcher that matches any of several sentinel errors.
+// Each sentinel is compared using errors.Is.
+//
+// Example:
+//
+//	matcher := OnSentinels([]error{context.Canceled, context.DeadlineExceeded}, func(e error) error {
+//	    return fmt.Errorf("request aborted: %w", e)
+//	})
+func OnSentinels(sentinelErrs []error, handler ErrorHandler) ErrorMatcher {
+	return On(IsAny(sentinelErrs...), handler)
+}
+
+// OnMessage creates an ErrorMatcher for errors whose message contains substr.
+// This is intended for third-party errors that expose neither a sentinel value
+// nor a dedicated type.
+//
+// Example:
+//
+//	matcher := OnMessage("connection reset by peer", func(e error) error {
+//	    return ErrConnectionLost
+//	})
+func OnMessage(substr string, handler ErrorHandler) ErrorMatcher {
+	return On(HasMessage(substr), handler)
+}
+
+// OnRegexp creates an ErrorMatcher for errors whose message matches re.
+//
+// Example:
+//
+//	matcher := OnRegexp(regexp.MustCompile(`^dial tcp .*: i/o timeout$`), func(e error) error {
+//	    return ErrDialTimeout
+//	})
+func OnRegexp(re *regexp.Regexp, handler ErrorHandler) ErrorMatcher {
+	return On(MatchesMessage(re), handler)
+}
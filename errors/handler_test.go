@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -211,7 +212,7 @@ func TestHandleErrorOrDie(t *testing.T) {
 	t.Run("HandleErrorOrDie panics on unmatched error", func(t *testing.T) {
 		err := errors.New("unmatched error")
 
-		assert.Panics(t, func() {
+		assert.PanicsWithError(t, "unhandled error of type *errors.errorString: unmatched error", func() {
 			HandleErrorOrDie(err,
 				OnSentinelError(io.EOF, func(e error) error {
 					return nil
@@ -538,8 +539,9 @@ func TestOnSentinelError(t *testing.T) {
 		handler := func(e error) error { return e }
 		matcher := OnSentinelError(io.EOF, handler)
 
-		assert.Equal(t, io.EOF, matcher.ErrorType)
-		assert.True(t, matcher.IsSentinel)
+		assert.True(t, matcher.Match(io.EOF))
+		assert.True(t, matcher.Match(fmt.Errorf("wrapped: %w", io.EOF)))
+		assert.False(t, matcher.Match(io.ErrUnexpectedEOF))
 		assert.NotNil(t, matcher.Handler)
 	})
 
@@ -573,7 +575,8 @@ func TestOnCustomError(t *testing.T) {
 			return fmt.Errorf("handled: %d", e.Code)
 		})
 
-		assert.False(t, matcher.IsSentinel)
+		assert.True(t, matcher.Match(&CustomError{Code: 200}))
+		assert.False(t, matcher.Match(&ValidationError{}))
 		assert.NotNil(t, matcher.Handler)
 
 		// Test the handler works
@@ -623,13 +626,32 @@ func TestOnCustomError(t *testing.T) {
 	})
 }
 
+func TestErrorMatcherFields(t *testing.T) {
+	t.Run("Handle uses a replaced Handler", func(t *testing.T) {
+		matcher := OnType(func(e *CustomError) error { return errors.New("original") })
+		matcher.Handler = func(error) error { return errors.New("replaced") }
+
+		handled, result := Handle(&CustomError{Code: 1}, matcher)
+		assert.True(t, handled)
+		require.EqualError(t, result, "replaced")
+	})
+
+	t.Run("Handle uses a replaced Condition", func(t *testing.T) {
+		matcher := OnType(func(e *CustomError) error { return nil })
+		matcher.Condition = func(error) bool { return false }
+
+		err := &CustomError{Code: 1}
+		handled, _ := Handle(err, matcher)
+		assert.False(t, handled)
+		assert.Equal(t, []error{err}, Uncovered([]ErrorMatcher{matcher}, err))
+	})
+}
+
 func TestErrorMatcherEdgeCases(t *testing.T) {
-	t.Run("Invalid sentinel error type in matcher", func(t *testing.T) {
-		// Create a matcher with invalid ErrorType for sentinel
+	t.Run("Matcher without condition", func(t *testing.T) {
+		// Create a matcher with no condition
 		matcher := ErrorMatcher{
-			ErrorType:  "not an error", // Invalid type
-			Handler:    func(e error) error { return e },
-			IsSentinel: true,
+			Handler: func(e error) error { return e },
 		}
 
 		err := errors.New("test error")
@@ -664,3 +686,253 @@ func TestErrorMatcherEdgeCases(t *testing.T) {
 		assert.EqualError(t, result, "found custom error at code 500")
 	})
 }
+
+func TestConditionMatchers(t *testing.T) {
+	t.Run("On with predicate", func(t *testing.T) {
+		matcher := On(func(e error) bool {
+			var ce *CustomError
+			return errors.As(e, &ce) && ce.Code >= 500
+		}, func(e error) error {
+			return errors.New("server error")
+		})
+
+		handled, result := Handle(&CustomError{Code: 503}, matcher)
+		assert.True(t, handled)
+		require.EqualError(t, result, "server error")
+
+		handled, _ = Handle(&CustomError{Code: 404}, matcher)
+		assert.False(t, handled)
+	})
+
+	t.Run("On with combinators", func(t *testing.T) {
+		matcher := On(And(HasType[*ValidationError](), Not(HasMessage("password"))), func(e error) error {
+			return errors.New("invalid input")
+		})
+
+		handled, result := Handle(&ValidationError{Field: "email", Value: "x"}, matcher)
+		assert.True(t, handled)
+		require.EqualError(t, result, "invalid input")
+
+		handled, _ = Handle(&ValidationError{Field: "password", Value: "x"}, matcher)
+		assert.False(t, handled)
+	})
+
+	t.Run("OnSentinels matches any sentinel", func(t *testing.T) {
+		callCount := 0
+		matcher := OnSentinels([]error{context.Canceled, context.DeadlineExceeded}, func(e error) error {
+			callCount++
+			return nil
+		})
+
+		handled, result := Handle(context.Canceled, matcher)
+		assert.True(t, handled)
+		require.NoError(t, result)
+
+		handled, result = Handle(fmt.Errorf("wrapped: %w", context.DeadlineExceeded), matcher)
+		assert.True(t, handled)
+		require.NoError(t, result)
+		assert.Equal(t, 2, callCount)
+
+		handled, _ = Handle(io.EOF, matcher)
+		assert.False(t, handled)
+	})
+
+	t.Run("OnMessage matches substring", func(t *testing.T) {
+		matcher := OnMessage("connection reset", func(e error) error {
+			return errors.New("connection lost")
+		})
+
+		handled, result := Handle(errors.New("read tcp: connection reset by peer"), matcher)
+		assert.True(t, handled)
+		require.EqualError(t, result, "connection lost")
+
+		handled, _ = Handle(errors.New("connection refused"), matcher)
+		assert.False(t, handled)
+	})
+
+	t.Run("OnRegexp matches pattern", func(t *testing.T) {
+		matcher := OnRegexp(regexp.MustCompile(`status \d{3}$`), func(e error) error {
+			return errors.New("http failure")
+		})
+
+		handled, result := Handle(errors.New("request failed with status 502"), matcher)
+		assert.True(t, handled)
+		require.EqualError(t, result, "http failure")
+
+		handled, _ = Handle(errors.New("request failed with status unknown"), matcher)
+		assert.False(t, handled)
+	})
+
+	t.Run("ErrorMatcher Match method", func(t *testing.T) {
+		matcher := On(IsAny(io.EOF), nil)
+		assert.True(t, matcher.Match(io.EOF))
+		assert.False(t, matcher.Match(io.ErrClosedPipe))
+		assert.False(t, ErrorMatcher{}.Match(io.EOF))
+	})
+}
+
+func TestHandleErrorAllocations(t *testing.T) {
+	wrappedSentinel := fmt.Errorf("wrapped: %w", io.EOF)
+	wrappedCustom := fmt.Errorf("wrapped: %w", &CustomError{Code: 500})
+	matchers := []ErrorMatcher{
+		OnSentinel(context.Canceled, func(e error) error { return nil }),
+		OnType(func(e *ValidationError) error { return nil }),
+		OnType(func(e *CustomError) error { return nil }),
+		OnSentinel(io.EOF, func(e error) error { return nil }),
+	}
+
+	t.Run("sentinel match does not allocate", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			Handle(wrappedSentinel, matchers...)
+		})
+		assert.Zero(t, allocs)
+	})
+
+	t.Run("type match does not allocate", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			Handle(wrappedCustom, matchers...)
+		})
+		assert.Zero(t, allocs)
+	})
+
+	t.Run("type handler runs once per match", func(t *testing.T) {
+		callCount := 0
+		handled, _ := Handle(wrappedCustom, OnType(func(e *CustomError) error {
+			callCount++
+			return nil
+		}))
+		assert.True(t, handled)
+		assert.Equal(t, 1, callCount)
+	})
+}
+
+func BenchmarkHandle(b *testing.B) {
+	wrappedSentinel := fmt.Errorf("wrapped: %w", io.EOF)
+	wrappedCustom := fmt.Errorf("wrapped: %w", &CustomError{Code: 500})
+	unmatched := errors.New("unmatched")
+	matchers := []ErrorMatcher{
+		OnSentinel(context.Canceled, func(e error) error { return nil }),
+		OnType(func(e *ValidationError) error { return nil }),
+		OnType(func(e *CustomError) error { return nil }),
+		OnSentinel(io.EOF, func(e error) error { return nil }),
+	}
+
+	b.Run("sentinel", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			Handle(wrappedSentinel, matchers...)
+		}
+	})
+
+	b.Run("type", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			Handle(wrappedCustom, matchers...)
+		}
+	})
+
+	b.Run("unmatched", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			Handle(unmatched, matchers...)
+		}
+	})
+
+	// The cost of the second type lookup OnType makes for a handled error.
+	b.Run("type lookup", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			asType[*CustomError](wrappedCustom)
+		}
+	})
+}
+
+func TestHandleAll(t *testing.T) {
+	t.Run("HandleAll with nil error", func(t *testing.T) {
+		unhandled, result := HandleAll(nil,
+			OnSentinel(io.EOF, func(e error) error { return e }),
+		)
+		assert.Empty(t, unhandled)
+		assert.NoError(t, result)
+	})
+
+	t.Run("HandleAll with single error", func(t *testing.T) {
+		unhandled, result := HandleAll(io.EOF,
+			OnSentinel(io.EOF, func(e error) error { return errors.New("handled EOF") }),
+		)
+		assert.Empty(t, unhandled)
+		assert.EqualError(t, result, "handled EOF")
+	})
+
+	t.Run("HandleAll handles each leaf", func(t *testing.T) {
+		var handledFields []string
+		joinedErr := errors.Join(
+			&ValidationError{Field: "email"},
+			io.EOF,
+			&ValidationError{Field: "name"},
+		)
+
+		unhandled, result := HandleAll(joinedErr,
+			OnSentinel(io.EOF, func(e error) error { return nil }),
+			OnType(func(e *ValidationError) error {
+				handledFields = append(handledFields, e.Field)
+				return fmt.Errorf("invalid %s", e.Field)
+			}),
+		)
+
+		assert.Empty(t, unhandled)
+		assert.Equal(t, []string{"email", "name"}, handledFields)
+		assert.EqualError(t, result, "invalid email\ninvalid name")
+	})
+
+	t.Run("HandleAll reports unhandled leaves", func(t *testing.T) {
+		unmatched1 := errors.New("unmatched 1")
+		unmatched2 := fmt.Errorf("wrapped: %w", &CustomError{Code: 500})
+		joinedErr := fmt.Errorf("batch: %w", errors.Join(unmatched1, io.EOF, unmatched2))
+
+		unhandled, result := HandleAll(joinedErr,
+			OnSentinel(io.EOF, func(e error) error { return errors.New("handled EOF") }),
+		)
+
+		assert.Equal(t, []error{unmatched1, unmatched2}, unhandled)
+		assert.EqualError(t, result, "handled EOF")
+	})
+
+	t.Run("HandleAll looks through single-element joins", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("batch: %w", errors.Join(nil, io.EOF))
+
+		var got error
+		unhandled, result := HandleAll(wrappedErr,
+			OnSentinel(io.EOF, func(e error) error {
+				got = e
+				return nil
+			}),
+		)
+
+		assert.Empty(t, unhandled)
+		assert.NoError(t, result)
+		assert.Equal(t, wrappedErr, got)
+	})
+
+	t.Run("HandleAll result preserves handler errors", func(t *testing.T) {
+		joinedErr := errors.Join(ErrSentinel1, ErrSentinel2)
+
+		unhandled, result := HandleAll(joinedErr,
+			OnSentinels([]error{ErrSentinel1, ErrSentinel2}, func(e error) error {
+				return fmt.Errorf("handled: %w", e)
+			}),
+		)
+
+		assert.Empty(t, unhandled)
+		require.ErrorIs(t, result, ErrSentinel1)
+		assert.ErrorIs(t, result, ErrSentinel2)
+	})
+
+	t.Run("HandleAll with all handlers suppressing", func(t *testing.T) {
+		unhandled, result := HandleAll(errors.Join(io.EOF, io.EOF),
+			OnSentinel(io.EOF, func(e error) error { return nil }),
+		)
+		assert.Empty(t, unhandled)
+		assert.NoError(t, result)
+	})
+}
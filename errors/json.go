@@ -0,0 +1,268 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+)
+
+// StackFrame is the serialized form of a single stack frame.
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// SerializedError is a stable, JSON-friendly representation of one layer of an
+// error tree. Each wrapper becomes a node whose children are the errors it wraps,
+// so a wrap chain becomes a path and an errors.Join becomes a node with several
+// children.
+//
+// Use Serialize to build it and SerializedError.Err to rebuild an error from it,
+// typically after sending it through a job queue or an RPC call.
+type SerializedError struct {
+	// Type is the Go type of the layer, such as "*errors.errorString".
+	Type string `json:"type"`
+	// Message is the full error message of the layer.
+	Message string `json:"message"`
+	// Code is the identity code of the layer if it is a registered sentinel.
+	Code string `json:"code,omitempty"`
+	// Kind is the name of the layer's Kind, if it carries one or is one of the
+	// context package's sentinels.
+	Kind string `json:"kind,omitempty"`
+	// Fields holds the fields attached to the layer with With.
+	Fields map[string]any `json:"fields,omitempty"`
+	// Stack holds the stack trace recorded by the layer, if any.
+	Stack []StackFrame `json:"stack,omitempty"`
+	// Children holds the errors wrapped by the layer.
+	Children []*SerializedError `json:"children,omitempty"`
+}
+
+// Serialize converts err and its whole wrap/join tree into a SerializedError.
+// It returns nil if err is nil.
+//
+// Example:
+//
+//	data, _ := json.Marshal(errors.Serialize(err))
+//	// {"type":"*errors.stackError","message":"load user: user not found","stack":[...],
+//	//  "children":[{"type":"*errors.stackError","message":"user not found","code":"users.not_found",...}]}
+func Serialize(err error) *SerializedError {
+	if err == nil {
+		return nil
+	}
+
+	s := &SerializedError{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+	}
+	if st, ok := err.(interface{ serializedType() string }); ok {
+		s.Type = st.serializedType()
+	}
+	if code, ok := codeOf(err); ok {
+		s.Code = code
+	}
+	if k, ok := err.(interface{ Kind() Kind }); ok {
+		s.Kind = k.Kind().String()
+	} else if s.Code == codeCanceled || s.Code == codeDeadlineExceeded {
+		s.Kind = KindOf(err).String()
+	}
+	if fc, ok := err.(fieldCarrier); ok {
+		s.Fields = attrsToMap(fc.ownFields())
+	}
+	if st, ok := err.(interface{ StackTrace() []runtime.Frame }); ok {
+		for _, frame := range st.StackTrace() {
+			s.Stack = append(s.Stack, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+	}
+
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if child := x.Unwrap(); child != nil {
+			s.Children = []*SerializedError{Serialize(child)}
+		}
+	case interface{ Unwrap() []error }:
+		for _, child := range x.Unwrap() {
+			if child != nil {
+				s.Children = append(s.Children, Serialize(child))
+			}
+		}
+	}
+	return s
+}
+
+// Err rebuilds an opaque error from the serialized tree.
+// It returns nil if s is nil.
+//
+// The rebuilt error has the original message, and its layers keep their kind,
+// fields and stack trace, so KindOf, Fields and %+v formatting behave as they did
+// for the original error. A layer with a code matches the sentinel registered
+// with that code in this process, so errors.Is and OnSentinel still work.
+// A leaf layer that was exactly a registered sentinel, such as
+// context.DeadlineExceeded, is rebuilt as that sentinel, so its own methods,
+// such as Timeout, are kept too.
+//
+// Example:
+//
+//	var s errors.SerializedError
+//	if err := json.Unmarshal(data, &s); err != nil {
+//	    return err
+//	}
+//	err := s.Err()
+//	errors.Is(err, ErrNotFound) // true if the original wrapped ErrNotFound
+func (s *SerializedError) Err() error {
+	if s == nil {
+		return nil
+	}
+	if sentinel := s.sentinel(); sentinel != nil {
+		return sentinel
+	}
+
+	re := &remoteError{
+		typ:  s.Type,
+		msg:  s.Message,
+		code: s.Code,
+	}
+	for _, key := range slices.Sorted(maps.Keys(s.Fields)) {
+		re.fields = append(re.fields, slog.Any(key, s.Fields[key]))
+	}
+	for _, frame := range s.Stack {
+		re.stack = append(re.stack, runtime.Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+	}
+	for _, child := range s.Children {
+		re.children = append(re.children, child.Err())
+	}
+
+	if kind, ok := ParseKind(s.Kind); ok {
+		return &remoteKindError{remoteError: re, kind: kind}
+	}
+	return re
+}
+
+// sentinel returns the sentinel registered in this process with the code of s,
+// if s is a leaf layer that carried nothing but that sentinel's message.
+func (s *SerializedError) sentinel() error {
+	if s.Code == "" || len(s.Children) > 0 || len(s.Fields) > 0 || len(s.Stack) > 0 {
+		return nil
+	}
+
+	registry.RLock()
+	sentinel, ok := registry.byCode[s.Code]
+	registry.RUnlock()
+	if !ok || sentinel.Error() != s.Message || fmt.Sprintf("%T", sentinel) != s.Type {
+		return nil
+	}
+	return sentinel
+}
+
+// attrsToMap converts slog attributes into a map suitable for JSON encoding.
+// Groups become nested maps.
+func attrsToMap(attrs []slog.Attr) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	result := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+		if value.Kind() == slog.KindGroup {
+			result[attr.Key] = attrsToMap(value.Group())
+			continue
+		}
+		result[attr.Key] = value.Any()
+	}
+	return result
+}
+
+// remoteError is an error rebuilt from a SerializedError.
+type remoteError struct {
+	typ      string
+	msg      string
+	code     string
+	fields   []slog.Attr
+	stack    []runtime.Frame
+	children []error
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Unwrap() []error {
+	return e.children
+}
+
+// Is reports whether target is the sentinel registered with the error's code,
+// or another rebuilt error with the same code.
+func (e *remoteError) Is(target error) bool {
+	if e.code == "" {
+		return false
+	}
+	code, ok := codeOf(target)
+	return ok && code == e.code
+}
+
+// StackTrace returns the stack trace of the original error, if it had one.
+func (e *remoteError) StackTrace() []runtime.Frame {
+	return e.stack
+}
+
+// Format implements fmt.Formatter.
+// The %s and %v verbs print the error message, %q prints it quoted, and %+v
+// prints the message and stack trace of every layer, as for the original error.
+func (e *remoteError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if state.Flag('+') {
+			fmt.Fprint(state, e.msg)
+			formatFrames(state, e.stack)
+			for _, child := range e.children {
+				formatCause(state, child, true)
+			}
+			return
+		}
+		fmt.Fprint(state, e.msg)
+	case 's':
+		fmt.Fprint(state, e.msg)
+	case 'q':
+		fmt.Fprintf(state, "%q", e.msg)
+	}
+}
+
+// LogValue implements slog.LogValuer. See LogValue for the layout.
+func (e *remoteError) LogValue() slog.Value {
+	return LogValue(e)
+}
+
+func (e *remoteError) ownFields() []slog.Attr {
+	return e.fields
+}
+
+func (e *remoteError) serializedType() string {
+	return e.typ
+}
+
+func (e *remoteError) identityCode() string {
+	return e.code
+}
+
+// remoteKindError is a rebuilt error whose original layer carried a Kind.
+type remoteKindError struct {
+	*remoteError
+
+	kind Kind
+}
+
+// Kind returns the kind of the original error layer.
+func (e *remoteKindError) Kind() Kind {
+	return e.kind
+}
@@ -0,0 +1,196 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTrip serializes err to JSON and rebuilds it.
+func roundTrip(t *testing.T, err error) error {
+	t.Helper()
+
+	data, marshalErr := json.Marshal(Serialize(err))
+	require.NoError(t, marshalErr)
+
+	var s SerializedError
+	require.NoError(t, json.Unmarshal(data, &s))
+	return s.Err()
+}
+
+func TestSerialize(t *testing.T) {
+	t.Run("Serialize nil error", func(t *testing.T) {
+		assert.Nil(t, Serialize(nil))
+		assert.NoError(t, (*SerializedError)(nil).Err())
+	})
+
+	t.Run("Serialize plain error", func(t *testing.T) {
+		assert.Equal(t, &SerializedError{
+			Type:    "*errors.errorString",
+			Message: "plain",
+		}, Serialize(errors.New("plain")))
+	})
+
+	t.Run("Serialize wrap chain", func(t *testing.T) {
+		customErr := &CustomError{Code: 400, Message: "bad"}
+		s := Serialize(fmt.Errorf("outer: %w", customErr))
+
+		assert.Equal(t, "*fmt.wrapError", s.Type)
+		assert.Equal(t, "outer: custom error 400: bad", s.Message)
+		require.Len(t, s.Children, 1)
+		assert.Equal(t, "*errors.CustomError", s.Children[0].Type)
+		assert.Empty(t, s.Children[0].Children)
+	})
+
+	t.Run("Serialize joined errors", func(t *testing.T) {
+		s := Serialize(errors.Join(io.EOF, io.ErrClosedPipe))
+		require.Len(t, s.Children, 2)
+		assert.Equal(t, "EOF", s.Children[0].Message)
+		assert.Equal(t, "io: read/write on closed pipe", s.Children[1].Message)
+	})
+
+	t.Run("Serialize metadata", func(t *testing.T) {
+		sentinel := registerTest(t, "test.serialize.metadata", errors.New("missing"))
+		err := WithKind(With(Wrap(sentinel, "load"), "user_id", 42, slog.Group("req", "id", "r-1")), NotFound)
+		s := Serialize(err)
+
+		assert.Equal(t, "NotFound", s.Kind)
+		fieldsLayer := s.Children[0]
+		assert.Equal(t, map[string]any{
+			"user_id": int64(42),
+			"req":     map[string]any{"id": "r-1"},
+		}, fieldsLayer.Fields)
+
+		stackLayer := fieldsLayer.Children[0]
+		require.NotEmpty(t, stackLayer.Stack)
+		assert.Contains(t, stackLayer.Stack[0].Function, "TestSerialize")
+		assert.True(t, strings.HasSuffix(stackLayer.Stack[0].File, "json_test.go"))
+		assert.Positive(t, stackLayer.Stack[0].Line)
+
+		assert.Equal(t, "test.serialize.metadata", stackLayer.Children[0].Code)
+	})
+
+	t.Run("Serialize records the kind of context errors", func(t *testing.T) {
+		s := Serialize(fmt.Errorf("query: %w", context.Canceled))
+		require.Len(t, s.Children, 1)
+		assert.Equal(t, "context.canceled", s.Children[0].Code)
+		assert.Equal(t, "Canceled", s.Children[0].Kind)
+	})
+
+	t.Run("Serialize produces stable JSON", func(t *testing.T) {
+		err := With(fmt.Errorf("wrapped: %w", io.EOF), "b", 2, "a", 1)
+		data, marshalErr := json.Marshal(Serialize(err))
+		require.NoError(t, marshalErr)
+		assert.JSONEq(t, `{
+			"type": "*errors.fieldsError",
+			"message": "wrapped: EOF",
+			"fields": {"a": 1, "b": 2},
+			"children": [{
+				"type": "*fmt.wrapError",
+				"message": "wrapped: EOF",
+				"children": [{"type": "*errors.errorString", "message": "EOF"}]
+			}]
+		}`, string(data))
+	})
+}
+
+func TestSerializedErrorErr(t *testing.T) {
+	sentinel := registerTest(t, "test.serialized.sentinel", errors.New("not found"))
+
+	t.Run("rebuilt error keeps message", func(t *testing.T) {
+		err := roundTrip(t, fmt.Errorf("load user: %w", sentinel))
+		assert.EqualError(t, err, "load user: not found")
+	})
+
+	t.Run("rebuilt error matches registered sentinel", func(t *testing.T) {
+		err := roundTrip(t, Wrap(fmt.Errorf("load user: %w", sentinel), "handle"))
+
+		require.ErrorIs(t, err, sentinel)
+		assert.NotErrorIs(t, err, io.EOF)
+
+		handled, result := Handle(err,
+			OnSentinelError(sentinel, func(e error) error { return errors.New("handled remotely") }),
+		)
+		assert.True(t, handled)
+		assert.EqualError(t, result, "handled remotely")
+
+		code, ok := CodeOf(err)
+		assert.True(t, ok)
+		assert.Equal(t, "test.serialized.sentinel", code)
+	})
+
+	t.Run("rebuilt error does not match unregistered sentinels", func(t *testing.T) {
+		err := roundTrip(t, fmt.Errorf("wrapped: %w", io.EOF))
+		assert.NotErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("rebuilt errors with same code match each other", func(t *testing.T) {
+		err1 := roundTrip(t, sentinel)
+		err2 := roundTrip(t, fmt.Errorf("wrapped: %w", sentinel))
+		assert.ErrorIs(t, err2, err1)
+	})
+
+	t.Run("rebuilt error keeps kind and fields", func(t *testing.T) {
+		err := roundTrip(t, fmt.Errorf("wrapped: %w", WithKind(With(io.EOF, "user_id", 42), PermissionDenied)))
+
+		assert.Equal(t, PermissionDenied, KindOf(err))
+		assert.Equal(t, []slog.Attr{slog.Any("user_id", float64(42))}, Fields(err))
+	})
+
+	t.Run("rebuilt error keeps joined children", func(t *testing.T) {
+		err := roundTrip(t, errors.Join(WithKind(io.EOF, NotFound), sentinel))
+
+		leafErrs := leaves(err)
+		require.Len(t, leafErrs, 2)
+		assert.Equal(t, NotFound, KindOf(leafErrs[0]))
+		assert.ErrorIs(t, leafErrs[1], sentinel)
+	})
+
+	t.Run("rebuilt error keeps stack trace", func(t *testing.T) {
+		original := Wrap(New("connection refused"), "dial")
+		err := roundTrip(t, original)
+
+		output := fmt.Sprintf("%+v", err)
+		assert.True(t, strings.HasPrefix(output, "dial: connection refused\n"))
+		assert.Contains(t, output, "\nconnection refused\n")
+		assert.Equal(t, 2, strings.Count(output, "json_test.go:"))
+		assert.Equal(t, "dial: connection refused", fmt.Sprintf("%v", err))
+		assert.Equal(t, `"dial: connection refused"`, fmt.Sprintf("%q", err))
+	})
+
+	t.Run("rebuilt error keeps context errors", func(t *testing.T) {
+		err := roundTrip(t, fmt.Errorf("query: %w", context.DeadlineExceeded))
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, DeadlineExceeded, KindOf(err))
+		assert.True(t, IsTimeout(err))
+		handled, _ := Handle(err, OnDeadlineExceeded(func(error) error { return nil }))
+		assert.True(t, handled)
+
+		err = roundTrip(t, fmt.Errorf("query: %w", context.Canceled))
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, Canceled, KindOf(err))
+		handled, _ = Handle(err, OnCanceled(func(error) error { return nil }))
+		assert.True(t, handled)
+	})
+
+	t.Run("rebuilt error serializes like the original", func(t *testing.T) {
+		original := errors.Join(WithKind(Wrap(sentinel, "load"), NotFound), With(io.EOF, "attempt", 2))
+		rebuilt := roundTrip(t, original)
+
+		data1, err := json.Marshal(Serialize(original))
+		require.NoError(t, err)
+		data2, err := json.Marshal(Serialize(rebuilt))
+		require.NoError(t, err)
+		assert.JSONEq(t, string(data1), string(data2))
+	})
+}
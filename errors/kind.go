@@ -0,0 +1,244 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Kind classifies an error into a small, transport-independent category.
+// The kinds follow the gRPC status codes, with the addition of Conflict,
+// and can be mapped to HTTP status codes and gRPC codes.
+type Kind int
+
+const (
+	// OK indicates the absence of an error.
+	OK Kind = iota
+	// Canceled indicates the operation was canceled, typically by the caller.
+	Canceled
+	// Unknown indicates an error that carries no kind.
+	Unknown
+	// InvalidArgument indicates the caller specified an invalid argument.
+	InvalidArgument
+	// DeadlineExceeded indicates the operation did not complete in time.
+	DeadlineExceeded
+	// NotFound indicates a requested entity was not found.
+	NotFound
+	// AlreadyExists indicates the entity the caller attempted to create already exists.
+	AlreadyExists
+	// PermissionDenied indicates the caller is not allowed to perform the operation.
+	PermissionDenied
+	// ResourceExhausted indicates a quota or rate limit was exceeded.
+	ResourceExhausted
+	// FailedPrecondition indicates the system is not in a state required for the operation.
+	FailedPrecondition
+	// Aborted indicates the operation was aborted, typically due to a concurrency issue.
+	Aborted
+	// OutOfRange indicates the operation was attempted past the valid range.
+	OutOfRange
+	// Unimplemented indicates the operation is not implemented or supported.
+	Unimplemented
+	// Internal indicates a broken invariant in the system.
+	Internal
+	// Unavailable indicates the service is currently unavailable and the call may be retried.
+	Unavailable
+	// DataLoss indicates unrecoverable data loss or corruption.
+	DataLoss
+	// Unauthenticated indicates the caller does not have valid credentials.
+	Unauthenticated
+	// Conflict indicates the request conflicts with the current state of the entity.
+	// It has no gRPC counterpart and maps to Aborted.
+	Conflict
+)
+
+// kindNames holds the names returned by Kind.String.
+var kindNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+	Conflict:           "Conflict",
+}
+
+// httpStatuses holds the HTTP status codes returned by Kind.HTTPStatus.
+var httpStatuses = [...]int{
+	OK:                 http.StatusOK,
+	Canceled:           499, // Client Closed Request
+	Unknown:            http.StatusInternalServerError,
+	InvalidArgument:    http.StatusBadRequest,
+	DeadlineExceeded:   http.StatusGatewayTimeout,
+	NotFound:           http.StatusNotFound,
+	AlreadyExists:      http.StatusConflict,
+	PermissionDenied:   http.StatusForbidden,
+	ResourceExhausted:  http.StatusTooManyRequests,
+	FailedPrecondition: http.StatusBadRequest,
+	Aborted:            http.StatusConflict,
+	OutOfRange:         http.StatusBadRequest,
+	Unimplemented:      http.StatusNotImplemented,
+	Internal:           http.StatusInternalServerError,
+	Unavailable:        http.StatusServiceUnavailable,
+	DataLoss:           http.StatusInternalServerError,
+	Unauthenticated:    http.StatusUnauthorized,
+	Conflict:           http.StatusConflict,
+}
+
+// valid reports whether k is one of the declared kinds.
+func (k Kind) valid() bool {
+	return k >= OK && int(k) < len(kindNames)
+}
+
+// String returns the name of the kind, such as "NotFound".
+func (k Kind) String() string {
+	if !k.valid() {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// ParseKind returns the Kind with the given name, as returned by Kind.String.
+//
+// Example:
+//
+//	kind, ok := errors.ParseKind("NotFound") // NotFound, true
+func ParseKind(name string) (Kind, bool) {
+	for kind, kindName := range kindNames {
+		if kindName == name {
+			return Kind(kind), true
+		}
+	}
+	return Unknown, false
+}
+
+// HTTPStatus returns the HTTP status code for the kind.
+// Canceled maps to the non-standard 499 (Client Closed Request), and
+// undeclared kinds map to 500 (Internal Server Error).
+//
+// Example:
+//
+//	w.WriteHeader(errors.KindOf(err).HTTPStatus())
+func (k Kind) HTTPStatus() int {
+	if !k.valid() {
+		return http.StatusInternalServerError
+	}
+	return httpStatuses[k]
+}
+
+// GRPCCode returns the gRPC status code for the kind.
+// The value can be converted directly to codes.Code from google.golang.org/grpc/codes.
+// Conflict maps to Aborted, and undeclared kinds map to Unknown.
+//
+// Example:
+//
+//	return status.Error(codes.Code(errors.KindOf(err).GRPCCode()), err.Error())
+func (k Kind) GRPCCode() uint32 {
+	switch {
+	case k == Conflict:
+		return uint32(Aborted)
+	case !k.valid():
+		return uint32(Unknown)
+	default:
+		return uint32(k)
+	}
+}
+
+// kindError is an error annotated with a Kind.
+type kindError struct {
+	wrapper
+
+	kind Kind
+}
+
+// Kind returns the kind attached to the error.
+func (e *kindError) Kind() Kind {
+	return e.kind
+}
+
+// WithKind attaches a Kind to err and returns the annotated error.
+// The error message is left unchanged, and the result unwraps to err so
+// errors.Is, errors.As and Handle still see the original error.
+// If err is nil, WithKind returns nil.
+//
+// Example:
+//
+//	if errors.Is(err, sql.ErrNoRows) {
+//	    return errors.WithKind(err, errors.NotFound)
+//	}
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{wrapper{err}, kind}
+}
+
+// KindOf returns the Kind of err, looking through the whole wrap chain.
+// The outermost kind wins. Errors implementing a Kind() Kind method report
+// their own kind, and context.Canceled and context.DeadlineExceeded map to
+// Canceled and DeadlineExceeded.
+//
+// It returns OK if err is nil, and Unknown if no kind is found.
+//
+// Example:
+//
+//	err := fmt.Errorf("load user: %w", errors.WithKind(sql.ErrNoRows, errors.NotFound))
+//	errors.KindOf(err)              // NotFound
+//	errors.KindOf(err).HTTPStatus() // 404
+func KindOf(err error) Kind {
+	if err == nil {
+		return OK
+	}
+
+	kind := Unknown
+	walk(err, func(e error) bool {
+		switch e { //nolint:errorlint // walk already visits every layer of the chain
+		case context.Canceled:
+			kind = Canceled
+			return false
+		case context.DeadlineExceeded:
+			kind = DeadlineExceeded
+			return false
+		}
+		if k, ok := e.(interface{ Kind() Kind }); ok {
+			kind = k.Kind()
+			return false
+		}
+		return true
+	})
+	return kind
+}
+
+// HasKind returns a Condition that matches errors whose KindOf is kind.
+//
+// Example:
+//
+//	cond := Or(HasKind(errors.Unavailable), HasKind(errors.DeadlineExceeded))
+func HasKind(kind Kind) Condition {
+	return func(err error) bool {
+		return KindOf(err) == kind
+	}
+}
+
+// OnKind creates an ErrorMatcher for errors of the given Kind, as reported by KindOf.
+//
+// Example:
+//
+//	handled, result := Handle(err,
+//	    OnKind(errors.NotFound, func(e error) error {
+//	        return nil // missing entries are fine here
+//	    }),
+//	)
+func OnKind(kind Kind, handler ErrorHandler) ErrorMatcher {
+	return On(HasKind(kind), handler)
+}
@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// kindedError reports its own kind through a Kind method.
+type kindedError struct{}
+
+func (kindedError) Error() string { return "kinded" }
+
+func (kindedError) Kind() Kind { return PermissionDenied }
+
+func TestKind(t *testing.T) {
+	t.Run("String returns kind name", func(t *testing.T) {
+		assert.Equal(t, "OK", OK.String())
+		assert.Equal(t, "NotFound", NotFound.String())
+		assert.Equal(t, "Conflict", Conflict.String())
+		assert.Equal(t, "Kind(100)", Kind(100).String())
+		assert.Equal(t, "Kind(-1)", Kind(-1).String())
+	})
+
+	t.Run("HTTPStatus maps kinds", func(t *testing.T) {
+		tests := map[Kind]int{
+			OK:                 http.StatusOK,
+			Canceled:           499,
+			Unknown:            http.StatusInternalServerError,
+			InvalidArgument:    http.StatusBadRequest,
+			DeadlineExceeded:   http.StatusGatewayTimeout,
+			NotFound:           http.StatusNotFound,
+			AlreadyExists:      http.StatusConflict,
+			PermissionDenied:   http.StatusForbidden,
+			ResourceExhausted:  http.StatusTooManyRequests,
+			FailedPrecondition: http.StatusBadRequest,
+			Aborted:            http.StatusConflict,
+			OutOfRange:         http.StatusBadRequest,
+			Unimplemented:      http.StatusNotImplemented,
+			Internal:           http.StatusInternalServerError,
+			Unavailable:        http.StatusServiceUnavailable,
+			DataLoss:           http.StatusInternalServerError,
+			Unauthenticated:    http.StatusUnauthorized,
+			Conflict:           http.StatusConflict,
+			Kind(100):          http.StatusInternalServerError,
+		}
+		for kind, status := range tests {
+			assert.Equal(t, status, kind.HTTPStatus(), "kind: %s", kind)
+		}
+	})
+
+	t.Run("GRPCCode maps kinds", func(t *testing.T) {
+		// Values of google.golang.org/grpc/codes
+		assert.Equal(t, uint32(0), OK.GRPCCode())
+		assert.Equal(t, uint32(1), Canceled.GRPCCode())
+		assert.Equal(t, uint32(2), Unknown.GRPCCode())
+		assert.Equal(t, uint32(3), InvalidArgument.GRPCCode())
+		assert.Equal(t, uint32(5), NotFound.GRPCCode())
+		assert.Equal(t, uint32(10), Aborted.GRPCCode())
+		assert.Equal(t, uint32(14), Unavailable.GRPCCode())
+		assert.Equal(t, uint32(16), Unauthenticated.GRPCCode())
+		assert.Equal(t, uint32(10), Conflict.GRPCCode())
+		assert.Equal(t, uint32(2), Kind(100).GRPCCode())
+	})
+}
+
+func TestWithKind(t *testing.T) {
+	t.Run("WithKind nil error", func(t *testing.T) {
+		assert.NoError(t, WithKind(nil, NotFound))
+	})
+
+	t.Run("WithKind keeps message and chain", func(t *testing.T) {
+		customErr := &CustomError{Code: 404}
+		err := WithKind(customErr, NotFound)
+
+		assert.EqualError(t, err, customErr.Error())
+		require.ErrorIs(t, err, customErr)
+		var target *CustomError
+		assert.ErrorAs(t, err, &target)
+	})
+
+	t.Run("WithKind formats wrapped error", func(t *testing.T) {
+		err := WithKind(New("missing"), NotFound)
+		assert.Equal(t, "missing", fmt.Sprintf("%s", err))
+		assert.Contains(t, fmt.Sprintf("%+v", err), "kind_test.go:")
+	})
+}
+
+func TestKindOf(t *testing.T) {
+	t.Run("KindOf nil error", func(t *testing.T) {
+		assert.Equal(t, OK, KindOf(nil))
+	})
+
+	t.Run("KindOf error without kind", func(t *testing.T) {
+		assert.Equal(t, Unknown, KindOf(io.EOF))
+	})
+
+	t.Run("KindOf wrapped error", func(t *testing.T) {
+		err := fmt.Errorf("load user: %w", Wrap(WithKind(io.EOF, NotFound), "query"))
+		assert.Equal(t, NotFound, KindOf(err))
+	})
+
+	t.Run("KindOf outermost kind wins", func(t *testing.T) {
+		err := WithKind(fmt.Errorf("call: %w", WithKind(io.EOF, Unavailable)), Internal)
+		assert.Equal(t, Internal, KindOf(err))
+	})
+
+	t.Run("KindOf context errors", func(t *testing.T) {
+		assert.Equal(t, Canceled, KindOf(fmt.Errorf("wrapped: %w", context.Canceled)))
+		assert.Equal(t, DeadlineExceeded, KindOf(context.DeadlineExceeded))
+	})
+
+	t.Run("KindOf error with Kind method", func(t *testing.T) {
+		assert.Equal(t, PermissionDenied, KindOf(fmt.Errorf("wrapped: %w", kindedError{})))
+	})
+
+	t.Run("KindOf joined errors", func(t *testing.T) {
+		err := errors.Join(io.EOF, WithKind(io.EOF, InvalidArgument))
+		assert.Equal(t, InvalidArgument, KindOf(err))
+	})
+}
+
+func TestOnKind(t *testing.T) {
+	notFound := fmt.Errorf("load: %w", WithKind(io.EOF, NotFound))
+	invalid := WithKind(&ValidationError{Field: "email"}, InvalidArgument)
+	matchers := []ErrorMatcher{
+		OnKind(NotFound, func(e error) error { return errors.New("not found handler") }),
+		OnKind(InvalidArgument, func(e error) error { return errors.New("invalid handler") }),
+	}
+
+	handled, result := Handle(notFound, matchers...)
+	assert.True(t, handled)
+	require.EqualError(t, result, "not found handler")
+
+	handled, result = Handle(invalid, matchers...)
+	assert.True(t, handled)
+	require.EqualError(t, result, "invalid handler")
+
+	handled, _ = Handle(io.EOF, matchers...)
+	assert.False(t, handled)
+
+	assert.True(t, HasKind(Unknown)(io.EOF))
+}
+
+func TestParseKind(t *testing.T) {
+	for kind := OK; kind <= Conflict; kind++ {
+		parsed, ok := ParseKind(kind.String())
+		assert.True(t, ok)
+		assert.Equal(t, kind, parsed)
+	}
+
+	parsed, ok := ParseKind("NoSuchKind")
+	assert.False(t, ok)
+	assert.Equal(t, Unknown, parsed)
+}
@@ -0,0 +1,206 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FieldError is an error tagged with the path of the value it is about,
+// such as "users[3].email".
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Path builds a field path from its elements: ints become indexes and other
+// values are formatted with fmt and joined with dots, so Path("users", 3, "email")
+// is "users[3].email".
+//
+// Example:
+//
+//	for i, user := range users {
+//	    if user.Email == "" {
+//	        list.AddPath(errors.Path("users", i, "email"), ErrRequired)
+//	    }
+//	}
+func Path(elems ...any) string {
+	var b strings.Builder
+	for _, elem := range elems {
+		switch x := elem.(type) {
+		case int:
+			b.WriteString("[" + strconv.Itoa(x) + "]")
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			fmt.Fprint(&b, x)
+		}
+	}
+	return b.String()
+}
+
+// List accumulates errors, typically the problems found while validating
+// a value, so that all of them can be reported at once.
+//
+// Errors added with the same path and message are kept only once, and when
+// Limit is set, errors beyond it are counted but not kept. A List is itself an
+// error: it renders as a multi-line message, marshals to JSON, and implements
+// Unwrap() []error so errors.Is, errors.As and HandleAll see every error in it.
+//
+// The zero value is an empty List without a limit. A List is not safe for
+// concurrent use.
+type List struct {
+	// Limit is the maximum number of errors kept. Zero means no limit.
+	Limit int
+
+	errs    []error
+	seen    map[string]struct{}
+	omitted int
+}
+
+// Add adds err to the list, unless it is nil or a duplicate.
+func (l *List) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	key := err.Error()
+	if fe, ok := err.(*FieldError); ok { //nolint:errorlint // only the top layer carries the path
+		key = fe.Path + "\x00" + fe.Err.Error()
+	}
+	if _, dup := l.seen[key]; dup {
+		return
+	}
+	if l.seen == nil {
+		l.seen = make(map[string]struct{})
+	}
+	l.seen[key] = struct{}{}
+
+	if l.Limit > 0 && len(l.errs) >= l.Limit {
+		l.omitted++
+		return
+	}
+	l.errs = append(l.errs, err)
+}
+
+// AddPath adds err to the list, tagged with path as a *FieldError.
+// A nil err is ignored.
+//
+// Example:
+//
+//	var list errors.List
+//	if !strings.Contains(user.Email, "@") {
+//	    list.AddPath("email", ErrInvalidEmail)
+//	}
+//	if user.Age < 0 {
+//	    list.AddPath("age", &RangeError{Min: 0})
+//	}
+//	return list.Err()
+func (l *List) AddPath(path string, err error) {
+	if err == nil {
+		return
+	}
+	l.Add(&FieldError{Path: path, Err: err})
+}
+
+// Len returns the number of errors added, including the ones omitted because
+// of the limit but not the duplicates.
+func (l *List) Len() int {
+	return len(l.errs) + l.omitted
+}
+
+// Errors returns the errors kept in the list, in the order they were added.
+func (l *List) Errors() []error {
+	return slices.Clone(l.errs)
+}
+
+// Err returns a snapshot of the list as an error, or nil if the list is empty.
+// Errors added later do not change the returned error.
+func (l *List) Err() error {
+	if l.Len() == 0 {
+		return nil
+	}
+	return &List{
+		Limit:   l.Limit,
+		errs:    slices.Clone(l.errs),
+		omitted: l.omitted,
+	}
+}
+
+// Error returns the errors one per line, preceded by their count. A list with
+// a single error returns that error's message.
+//
+// Example:
+//
+//	3 errors:
+//	  - users[3].email: invalid format
+//	  - users[5].name: required
+//	  - ... and 1 more
+func (l *List) Error() string {
+	if len(l.errs) == 1 && l.omitted == 0 {
+		return l.errs[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors:", l.Len())
+	for _, err := range l.errs {
+		b.WriteString("\n  - ")
+		b.WriteString(strings.ReplaceAll(err.Error(), "\n", "\n    "))
+	}
+	if l.omitted > 0 {
+		fmt.Fprintf(&b, "\n  - ... and %d more", l.omitted)
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors kept in the list.
+func (l *List) Unwrap() []error {
+	return l.errs
+}
+
+// listJSON is the JSON layout of a List.
+type listJSON struct {
+	Errors  []listEntryJSON `json:"errors"`
+	Omitted int             `json:"omitted,omitempty"`
+}
+
+// listEntryJSON is the JSON layout of a single error in a List.
+type listEntryJSON struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON encodes the list as an object holding its errors, each with its
+// path and message, and the number of errors omitted because of the limit.
+//
+// Example:
+//
+//	{"errors":[{"path":"users[3].email","message":"invalid format"}],"omitted":1}
+func (l *List) MarshalJSON() ([]byte, error) {
+	out := listJSON{
+		Errors:  make([]listEntryJSON, 0, len(l.errs)),
+		Omitted: l.omitted,
+	}
+	for _, err := range l.errs {
+		entry := listEntryJSON{Message: err.Error()}
+		if fe, ok := err.(*FieldError); ok { //nolint:errorlint // only the top layer carries the path
+			entry.Path, entry.Message = fe.Path, fe.Err.Error()
+		}
+		out.Errors = append(out.Errors, entry)
+	}
+	return json.Marshal(out)
+}
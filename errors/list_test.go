@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldError(t *testing.T) {
+	err := &FieldError{Path: "users[3].email", Err: io.EOF}
+	require.EqualError(t, err, "users[3].email: EOF")
+	require.ErrorIs(t, err, io.EOF)
+
+	require.EqualError(t, &FieldError{Err: io.EOF}, "EOF")
+}
+
+func TestPath(t *testing.T) {
+	assert.Empty(t, Path())
+	assert.Equal(t, "users", Path("users"))
+	assert.Equal(t, "users[3].email", Path("users", 3, "email"))
+	assert.Equal(t, "matrix[1][2]", Path("matrix", 1, 2))
+	assert.Equal(t, "[0].name", Path(0, "name"))
+}
+
+func TestList(t *testing.T) {
+	t.Run("List empty", func(t *testing.T) {
+		var list List
+		list.Add(nil)
+		list.AddPath("name", nil)
+		assert.Zero(t, list.Len())
+		assert.NoError(t, list.Err())
+	})
+
+	t.Run("List with a single error", func(t *testing.T) {
+		var list List
+		list.AddPath("name", io.EOF)
+		require.EqualError(t, list.Err(), "name: EOF")
+	})
+
+	t.Run("List with several errors", func(t *testing.T) {
+		var list List
+		list.AddPath(Path("users", 3, "email"), ErrSentinel1)
+		list.Add(io.EOF)
+		list.AddPath("name", Join(ErrSentinel2, io.ErrUnexpectedEOF))
+
+		err := list.Err()
+		require.EqualError(t, err, "3 errors:\n"+
+			"  - users[3].email: sentinel error 1\n"+
+			"  - EOF\n"+
+			"  - name: sentinel error 2\n"+
+			"    unexpected EOF")
+		require.ErrorIs(t, err, ErrSentinel1)
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+		var fe *FieldError
+		require.ErrorAs(t, err, &fe)
+		assert.Equal(t, "users[3].email", fe.Path)
+	})
+
+	t.Run("List deduplicates errors", func(t *testing.T) {
+		var list List
+		list.AddPath("email", ErrSentinel1)
+		list.AddPath("email", errors.New("sentinel error 1"))
+		list.AddPath("name", ErrSentinel1)
+		list.Add(io.EOF)
+		list.Add(io.EOF)
+
+		assert.Equal(t, 3, list.Len())
+		assert.Len(t, list.Errors(), 3)
+	})
+
+	t.Run("List with limit", func(t *testing.T) {
+		list := List{Limit: 2}
+		for i := range 5 {
+			list.AddPath(Path("items", i), ErrSentinel1)
+		}
+		list.AddPath(Path("items", 4), ErrSentinel1) // duplicate, not counted
+
+		assert.Equal(t, 5, list.Len())
+		assert.Len(t, list.Errors(), 2)
+		require.EqualError(t, list.Err(), "5 errors:\n"+
+			"  - items[0]: sentinel error 1\n"+
+			"  - items[1]: sentinel error 1\n"+
+			"  - ... and 3 more")
+	})
+
+	t.Run("List Err is a snapshot", func(t *testing.T) {
+		var list List
+		list.Add(io.EOF)
+		err := list.Err()
+		list.Add(io.ErrUnexpectedEOF)
+
+		require.EqualError(t, err, "EOF")
+		assert.Equal(t, 2, list.Len())
+	})
+
+	t.Run("List with HandleAll", func(t *testing.T) {
+		var list List
+		list.AddPath("a", ErrSentinel1)
+		list.AddPath("b", &ValidationError{Field: "b"})
+		list.AddPath("c", io.EOF)
+
+		unhandled, result := HandleAll(fmt.Errorf("validate: %w", list.Err()),
+			OnSentinel(ErrSentinel1, func(e error) error { return nil }),
+			OnType(func(e *ValidationError) error { return fmt.Errorf("invalid %s", e.Field) }),
+		)
+		require.Len(t, unhandled, 1)
+		require.EqualError(t, unhandled[0], "c: EOF")
+		require.EqualError(t, result, "invalid b")
+	})
+}
+
+func TestListJSON(t *testing.T) {
+	t.Run("List JSON", func(t *testing.T) {
+		list := List{Limit: 2}
+		list.AddPath("users[3].email", ErrSentinel1)
+		list.Add(io.EOF)
+		list.AddPath("name", io.EOF)
+
+		data, err := json.Marshal(list.Err())
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"errors": [
+				{"path": "users[3].email", "message": "sentinel error 1"},
+				{"message": "EOF"}
+			],
+			"omitted": 1
+		}`, string(data))
+	})
+
+	t.Run("List JSON empty", func(t *testing.T) {
+		data, err := json.Marshal(&List{})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"errors": []}`, string(data))
+	})
+}
@@ -0,0 +1,109 @@
+package errors
+
+// MatchCase maps an error to a result of type R.
+// Cases are created with Case, CaseType or Default and evaluated by Match.
+type MatchCase[R any] struct {
+	eval func(error) (R, bool)
+}
+
+// Match evaluates err against a list of cases and returns the result of the first
+// case that matches, together with true.
+// If no case matches, or err is nil, it returns the zero value of R and false.
+//
+// Match is the value-returning counterpart of Handle: instead of mapping an error
+// to another error, it maps it to an arbitrary value such as an HTTP status code,
+// a retry decision or a user-facing message.
+//
+// Example:
+//
+//	status, _ := Match(err,
+//	    Case(ErrNotFound, func(e error) int {
+//	        return http.StatusNotFound
+//	    }),
+//	    CaseType(func(e *ValidationError) int {
+//	        return http.StatusBadRequest
+//	    }),
+//	    Default(func(e error) int {
+//	        return http.StatusInternalServerError
+//	    }),
+//	)
+func Match[R any](err error, cases ...MatchCase[R]) (R, bool) {
+	if err != nil {
+		for _, c := range cases {
+			if result, ok := c.eval(err); ok {
+				return result, true
+			}
+		}
+	}
+
+	var zero R
+	return zero, false
+}
+
+// Case creates a MatchCase for sentinel errors.
+// The sentinel is compared using errors.Is, so wrapped sentinels match as well.
+//
+// Example:
+//
+//	c := Case(io.EOF, func(e error) bool {
+//	    return false // EOF is not retryable
+//	})
+func Case[R any](sentinelErr error, fn func(error) R) MatchCase[R] {
+	return CaseIf(IsAny(sentinelErr), fn)
+}
+
+// CaseType creates a MatchCase for custom error types.
+// The type parameter T is matched using errors.As semantics, and fn receives the unwrapped
+// typed error, allowing the result to depend on type-specific fields.
+//
+// Example:
+//
+//	c := CaseType(func(e *ValidationError) string {
+//	    return fmt.Sprintf("field %s is invalid", e.Field)
+//	})
+func CaseType[T error, R any](fn func(T) R) MatchCase[R] {
+	return MatchCase[R]{
+		eval: func(err error) (R, bool) {
+			if typedErr, ok := asType[T](err); ok {
+				return fn(typedErr), true
+			}
+			var zero R
+			return zero, false
+		},
+	}
+}
+
+// Default creates a MatchCase that matches every non-nil error.
+// It is typically passed as the last case so that Match always produces a result.
+//
+// Example:
+//
+//	c := Default(func(e error) int {
+//	    return http.StatusInternalServerError
+//	})
+func Default[R any](fn func(error) R) MatchCase[R] {
+	return MatchCase[R]{
+		eval: func(err error) (R, bool) {
+			return fn(err), true
+		},
+	}
+}
+
+// CaseIf creates a MatchCase for an arbitrary Condition.
+//
+// Example:
+//
+//	c := CaseIf(HasMessage("too many connections"), func(e error) time.Duration {
+//	    return 5 * time.Second
+//	})
+func CaseIf[R any](cond Condition, fn func(error) R) MatchCase[R] {
+	return MatchCase[R]{
+		eval: func(err error) (R, bool) {
+			if cond(err) {
+				return fn(err), true
+			}
+			var zero R
+			return zero, false
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	t.Run("Match with nil error", func(t *testing.T) {
+		result, matched := Match(nil,
+			Default(func(e error) int { return http.StatusInternalServerError }),
+		)
+		assert.False(t, matched)
+		assert.Equal(t, 0, result)
+	})
+
+	t.Run("Match with sentinel case", func(t *testing.T) {
+		result, matched := Match(io.EOF,
+			Case(io.EOF, func(e error) string { return "end of file" }),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, "end of file", result)
+	})
+
+	t.Run("Match with wrapped sentinel", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("wrapped: %w", ErrSentinel1)
+		result, matched := Match(wrappedErr,
+			Case(ErrSentinel2, func(e error) int { return 2 }),
+			Case(ErrSentinel1, func(e error) int { return 1 }),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, 1, result)
+	})
+
+	t.Run("Match with type case", func(t *testing.T) {
+		wrappedErr := fmt.Errorf("wrapped: %w", &ValidationError{Field: "email", Value: ""})
+		result, matched := Match(wrappedErr,
+			CaseType(func(e *CustomError) string { return "custom" }),
+			CaseType(func(e *ValidationError) string { return "invalid " + e.Field }),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, "invalid email", result)
+	})
+
+	t.Run("Match with no matching case", func(t *testing.T) {
+		result, matched := Match(errors.New("unmatched"),
+			Case(io.EOF, func(e error) bool { return true }),
+			CaseType(func(e *CustomError) bool { return true }),
+		)
+		assert.False(t, matched)
+		assert.False(t, result)
+	})
+
+	t.Run("Match with default case", func(t *testing.T) {
+		var capturedErr error
+		err := errors.New("unmatched")
+		result, matched := Match(err,
+			Case(io.EOF, func(e error) int { return http.StatusOK }),
+			Default(func(e error) int {
+				capturedErr = e
+				return http.StatusInternalServerError
+			}),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, http.StatusInternalServerError, result)
+		assert.Equal(t, err, capturedErr)
+	})
+
+	t.Run("Match first matching case wins", func(t *testing.T) {
+		result, matched := Match(io.EOF,
+			Default(func(e error) string { return "default" }),
+			Case(io.EOF, func(e error) string { return "eof" }),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, "default", result)
+	})
+
+	t.Run("Match with value type error", func(t *testing.T) {
+		result, matched := Match(CustomError{Code: 404, Message: "not found"},
+			CaseType(func(e CustomError) int { return e.Code }),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, 404, result)
+	})
+}
+
+func TestCaseIf(t *testing.T) {
+	t.Run("CaseIf with condition", func(t *testing.T) {
+		result, matched := Match(errors.New("too many connections"),
+			CaseIf(IsAny(io.EOF), func(e error) string { return "eof" }),
+			CaseIf(HasMessage("too many"), func(e error) string { return "busy" }),
+		)
+		assert.True(t, matched)
+		assert.Equal(t, "busy", result)
+	})
+
+	t.Run("CaseIf with no match", func(t *testing.T) {
+		result, matched := Match(io.EOF,
+			CaseIf(Not(IsAny(io.EOF)), func(e error) int { return 1 }),
+		)
+		assert.False(t, matched)
+		assert.Equal(t, 0, result)
+	})
+}
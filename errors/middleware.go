@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Middleware wraps the handler of a matcher with cross-cutting behavior, such as
+// logging, metrics or tracing. It receives the matcher whose handler is being
+// wrapped and the next handler in the chain, and returns the handler to run instead.
+//
+// For default handlers, the matcher has a nil Condition and no name.
+type Middleware func(matcher ErrorMatcher, next ErrorHandler) ErrorHandler
+
+// Observe returns a Middleware that calls hook after every handler run, with the
+// matcher, the handled error and the handler's result. It is intended for
+// metrics and tracing, and does not change the result.
+//
+// Example:
+//
+//	errors.Observe(func(m errors.ErrorMatcher, err, result error) {
+//	    span.AddEvent("error handled", trace.WithAttributes(
+//	        attribute.String("error.type", fmt.Sprintf("%T", err)),
+//	    ))
+//	})
+func Observe(hook func(matcher ErrorMatcher, err, result error)) Middleware {
+	return func(matcher ErrorMatcher, next ErrorHandler) ErrorHandler {
+		return func(err error) error {
+			result := next(err)
+			hook(matcher, err, result)
+			return result
+		}
+	}
+}
+
+// LogHandled returns a Middleware that logs every handled error with logger at
+// the given level, together with the matcher's name, if it has one, and the
+// handler's result.
+//
+// Example:
+//
+//	handler = handler.Use(errors.LogHandled(slog.Default(), slog.LevelWarn))
+//	// level=WARN msg="error handled" err="load user: not found" result="user missing"
+func LogHandled(logger *slog.Logger, level slog.Level) Middleware {
+	return Observe(func(matcher ErrorMatcher, err, result error) {
+		attrs := make([]slog.Attr, 0, 3)
+		if matcher.Name != "" {
+			attrs = append(attrs, slog.String("matcher", matcher.Name))
+		}
+		attrs = append(attrs, slog.Any("err", LogValue(err)))
+		if result != nil {
+			attrs = append(attrs, slog.Any("result", LogValue(result)))
+		}
+		logger.LogAttrs(context.Background(), level, "error handled", attrs...)
+	})
+}
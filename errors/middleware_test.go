@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMiddleware appends name to calls before and after running the handler.
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(_ ErrorMatcher, next ErrorHandler) ErrorHandler {
+		return func(err error) error {
+			*calls = append(*calls, name+" before")
+			result := next(err)
+			*calls = append(*calls, name+" after")
+			return result
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type observation struct {
+		err, result error
+	}
+
+	var observed []observation
+	handler := NewHandler(
+		OnSentinel(io.EOF, func(e error) error { return nil }),
+	).Use(Observe(func(_ ErrorMatcher, err, result error) {
+		observed = append(observed, observation{err, result})
+	}))
+
+	wrapped := fmt.Errorf("read: %w", io.EOF)
+	handled, result := handler.Handle(wrapped)
+	assert.True(t, handled)
+	require.NoError(t, result)
+	assert.Equal(t, []observation{{wrapped, nil}}, observed)
+}
+
+func TestLogHandled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	handler := NewHandler(
+		OnSentinel(io.EOF, func(e error) error { return errors.New("stream closed") }).WithName("eof"),
+		OnSentinel(io.ErrClosedPipe, func(e error) error { return nil }),
+	).Use(LogHandled(logger, slog.LevelWarn))
+
+	_, _ = handler.Handle(With(io.EOF, "conn", 7))
+	_, _ = handler.Handle(io.ErrClosedPipe)
+	assert.Equal(t,
+		"level=WARN msg=\"error handled\" matcher=eof err.msg=EOF err.conn=7 result=\"stream closed\"\n"+
+			"level=WARN msg=\"error handled\" err=\"io: read/write on closed pipe\"\n",
+		buf.String(),
+	)
+}
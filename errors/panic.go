@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// PanicError is an error created from a recovered panic.
+// It keeps the value passed to panic and the stack of the panicking goroutine.
+// If the panic value is an error, PanicError unwraps to it, so errors.Is,
+// errors.As and Handle see the original error.
+type PanicError struct {
+	Value any
+	stack stack
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, or nil otherwise.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// StackTrace returns the stack of the goroutine at the point of the panic,
+// starting with the function that panicked, or nil if stack traces were
+// disabled with SetStackTraces.
+func (e *PanicError) StackTrace() []runtime.Frame {
+	frames := e.stack.frames()
+	// Drop the frames of Recover and of the runtime's panic machinery.
+	for i, frame := range frames {
+		if !strings.HasPrefix(frame.Function, "runtime.") && !strings.HasSuffix(frame.Function, "/errors.Recover") {
+			return frames[i:]
+		}
+	}
+	return frames
+}
+
+// Format implements fmt.Formatter.
+// The %s and %v verbs print the error message, %q prints it quoted, and %+v
+// also prints the stack of the panic and of any error passed to panic.
+func (e *PanicError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if state.Flag('+') {
+			fmt.Fprint(state, e.Error())
+			formatFrames(state, e.StackTrace())
+			formatCause(state, e.Unwrap(), true)
+			return
+		}
+		fmt.Fprint(state, e.Error())
+	case 's':
+		fmt.Fprint(state, e.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	}
+}
+
+// Recover converts a panic into a *PanicError stored in *errp.
+// It must be called directly with defer, typically with a named error result.
+// If *errp already holds an error, the two are joined.
+// If the goroutine is not panicking, Recover does nothing.
+//
+// Example:
+//
+//	func (w *Worker) process(job Job) (err error) {
+//	    defer errors.Recover(&err)
+//	    return w.handler(job)
+//	}
+//
+//	handled, result := errors.Handle(err,
+//	    errors.OnType(func(e *errors.PanicError) error {
+//	        log.Printf("job panicked: %+v", e)
+//	        return ErrJobCrashed
+//	    }),
+//	)
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	panicErr := &PanicError{
+		Value: r,
+		stack: callers(2),
+	}
+	if *errp == nil {
+		*errp = panicErr
+	} else {
+		*errp = errors.Join(*errp, panicErr)
+	}
+}
+
+// Try calls fn and returns its result.
+// If fn panics, Try returns the zero value of T and a *PanicError.
+//
+// Example:
+//
+//	value, err := errors.Try(func() int {
+//	    return parse(input) // may panic on malformed input
+//	})
+func Try[T any](fn func() T) (result T, err error) {
+	defer Recover(&err)
+	return fn(), nil
+}
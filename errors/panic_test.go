@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panicWith(value any) (err error) {
+	defer Recover(&err)
+	panic(value)
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("Recover without panic", func(t *testing.T) {
+		err := func() (err error) {
+			defer Recover(&err)
+			return io.EOF
+		}()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("Recover with string panic", func(t *testing.T) {
+		err := panicWith("something broke")
+
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "something broke", panicErr.Value)
+		assert.EqualError(t, err, "panic: something broke")
+		assert.NoError(t, panicErr.Unwrap())
+	})
+
+	t.Run("Recover with error panic", func(t *testing.T) {
+		customErr := &CustomError{Code: 500, Message: "boom"}
+		err := panicWith(customErr)
+
+		require.ErrorIs(t, err, customErr)
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Same(t, customErr, panicErr.Value)
+	})
+
+	t.Run("Recover with runtime error", func(t *testing.T) {
+		err := func() (err error) {
+			defer Recover(&err)
+			var m map[string]int
+			m["key"] = 1
+			return nil
+		}()
+
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Contains(t, err.Error(), "assignment to entry in nil map")
+	})
+
+	t.Run("Recover joins existing error", func(t *testing.T) {
+		err := func() (err error) {
+			defer Recover(&err)
+			err = io.EOF
+			panic("after error")
+		}()
+
+		require.ErrorIs(t, err, io.EOF)
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+	})
+
+	t.Run("Recover records panic stack", func(t *testing.T) {
+		err := panicWith("with stack")
+
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		frames := panicErr.StackTrace()
+		require.NotEmpty(t, frames)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "errors.panicWith"))
+
+		output := fmt.Sprintf("%+v", err)
+		assert.True(t, strings.HasPrefix(output, "panic: with stack\n"))
+		assert.Contains(t, output, "panic_test.go:")
+	})
+
+	t.Run("Recover with stack traces disabled", func(t *testing.T) {
+		SetStackTraces(false)
+		defer SetStackTraces(true)
+
+		err := panicWith("no stack")
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Empty(t, panicErr.StackTrace())
+		assert.Equal(t, "panic: no stack", fmt.Sprintf("%+v", err))
+	})
+
+	t.Run("Recover works with Handle", func(t *testing.T) {
+		err := panicWith(io.ErrUnexpectedEOF)
+
+		handled, result := Handle(err,
+			OnType(func(e *PanicError) error {
+				return fmt.Errorf("recovered: %v", e.Value)
+			}),
+		)
+		assert.True(t, handled)
+		assert.EqualError(t, result, "recovered: unexpected EOF")
+	})
+
+	t.Run("Recover keeps unhandled error from MustHandle", func(t *testing.T) {
+		original := errors.New("unmatched")
+		err := func() (err error) {
+			defer Recover(&err)
+			return MustHandle(original, OnSentinel(io.EOF, func(e error) error { return nil }))
+		}()
+
+		require.ErrorIs(t, err, original)
+		assert.EqualError(t, err, "panic: unhandled error of type *errors.errorString: unmatched")
+	})
+}
+
+func TestTry(t *testing.T) {
+	t.Run("Try without panic", func(t *testing.T) {
+		value, err := Try(func() int { return 42 })
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("Try with panic", func(t *testing.T) {
+		value, err := Try(func() string {
+			panic("bad input")
+		})
+
+		assert.Empty(t, value)
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "bad input", panicErr.Value)
+
+		frames := panicErr.StackTrace()
+		require.NotEmpty(t, frames)
+		assert.Contains(t, frames[0].Function, "TestTry")
+	})
+}
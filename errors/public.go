@@ -0,0 +1,106 @@
+package errors
+
+// PublicMessage is a message that is safe to show to end users, such as in an
+// API response. It is kept separate from the internal error message, which may
+// contain hostnames, queries or other details that must not leak.
+type PublicMessage struct {
+	// Key identifies the message for translation, such as "users.not_found".
+	Key string `json:"key"`
+	// Message is the default, untranslated text of the message.
+	Message string `json:"message"`
+	// Params holds the values to interpolate into the translated message.
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// DefaultPublicMessage is returned by Public for errors that carry no public message.
+var DefaultPublicMessage = PublicMessage{
+	Key:     "errors.internal",
+	Message: "An internal error occurred.",
+}
+
+// publicError is an error annotated with a public message.
+// The public message is not part of the error message.
+type publicError struct {
+	wrapper
+
+	public PublicMessage
+}
+
+// PublicMessage returns the public message attached to the error.
+func (e *publicError) PublicMessage() PublicMessage {
+	return e.public
+}
+
+// WithPublic attaches a public message to err and returns the annotated error.
+// The error message is left unchanged, so logs keep the internal details, and
+// the result unwraps to err so errors.Is, errors.As and Handle still see the
+// original error. If err is nil, WithPublic returns nil.
+//
+// Example:
+//
+//	if errors.Is(err, sql.ErrNoRows) {
+//	    return errors.WithPublic(err, errors.PublicMessage{
+//	        Key:     "users.not_found",
+//	        Message: "The user does not exist.",
+//	        Params:  map[string]any{"id": id},
+//	    })
+//	}
+func WithPublic(err error, public PublicMessage) error {
+	if err == nil {
+		return nil
+	}
+	return &publicError{wrapper{err}, public}
+}
+
+// Public returns the public message of err: the outermost message carried by
+// an error in its tree that implements a PublicMessage() PublicMessage method,
+// or DefaultPublicMessage if there is none. It returns the zero PublicMessage
+// if err is nil.
+//
+// Example:
+//
+//	func writeError(w http.ResponseWriter, err error) {
+//	    logger.Error("request failed", "err", err) // internal message
+//	    w.WriteHeader(errors.KindOf(err).HTTPStatus())
+//	    _ = json.NewEncoder(w).Encode(errors.Public(err)) // safe message
+//	}
+func Public(err error) PublicMessage {
+	if err == nil {
+		return PublicMessage{}
+	}
+
+	public := DefaultPublicMessage
+	walk(err, func(e error) bool {
+		if pm, ok := e.(interface{ PublicMessage() PublicMessage }); ok {
+			public = pm.PublicMessage()
+			return false
+		}
+		return true
+	})
+	return public
+}
+
+// ToPublic returns an ErrorHandler that attaches the given public message to
+// the handled error, for rewriting internal errors into public ones with Handle.
+//
+// Example:
+//
+//	err = errors.HandleOr(err,
+//	    errors.ToPublic(errors.DefaultPublicMessage),
+//	    errors.OnSentinel(ErrNotFound, errors.ToPublic(errors.PublicMessage{
+//	        Key:     "users.not_found",
+//	        Message: "The user does not exist.",
+//	    })),
+//	    errors.OnType(func(e *ValidationError) error {
+//	        return errors.WithPublic(e, errors.PublicMessage{
+//	            Key:     "validation.invalid_field",
+//	            Message: "A field is invalid.",
+//	            Params:  map[string]any{"field": e.Field},
+//	        })
+//	    }),
+//	)
+func ToPublic(public PublicMessage) ErrorHandler {
+	return func(err error) error {
+		return WithPublic(err, public)
+	}
+}
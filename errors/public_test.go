@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPublic(t *testing.T) {
+	notFound := PublicMessage{Key: "users.not_found", Message: "The user does not exist."}
+
+	t.Run("WithPublic with nil error", func(t *testing.T) {
+		assert.NoError(t, WithPublic(nil, notFound))
+	})
+
+	t.Run("WithPublic keeps the internal message", func(t *testing.T) {
+		err := WithPublic(fmt.Errorf("query db-1.internal: %w", io.EOF), notFound)
+		require.EqualError(t, err, "query db-1.internal: EOF")
+		require.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, "query db-1.internal: EOF", fmt.Sprintf("%v", err))
+	})
+
+	t.Run("WithPublic keeps fields and stack traces", func(t *testing.T) {
+		err := WithPublic(With(New("boom"), "user_id", 42), notFound)
+		assert.Len(t, Fields(err), 1)
+		assert.Contains(t, fmt.Sprintf("%+v", err), "TestWithPublic")
+	})
+}
+
+func TestPublic(t *testing.T) {
+	inner := PublicMessage{Key: "inner", Message: "Inner."}
+	outer := PublicMessage{Key: "outer", Message: "Outer.", Params: map[string]any{"id": 7}}
+
+	t.Run("Public with nil error", func(t *testing.T) {
+		assert.Equal(t, PublicMessage{}, Public(nil))
+	})
+
+	t.Run("Public without public message", func(t *testing.T) {
+		assert.Equal(t, DefaultPublicMessage, Public(io.EOF))
+	})
+
+	t.Run("Public with wrapped public message", func(t *testing.T) {
+		err := fmt.Errorf("handler: %w", WithPublic(io.EOF, inner))
+		assert.Equal(t, inner, Public(err))
+	})
+
+	t.Run("Public returns the outermost message", func(t *testing.T) {
+		err := WithPublic(Wrap(WithPublic(io.EOF, inner), "load"), outer)
+		assert.Equal(t, outer, Public(err))
+	})
+
+	t.Run("Public with joined errors", func(t *testing.T) {
+		err := Join(io.EOF, WithPublic(io.ErrUnexpectedEOF, inner))
+		assert.Equal(t, inner, Public(err))
+	})
+
+	t.Run("Public message JSON", func(t *testing.T) {
+		data, err := json.Marshal(Public(WithPublic(io.EOF, outer)))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"key":"outer","message":"Outer.","params":{"id":7}}`, string(data))
+	})
+}
+
+func TestToPublic(t *testing.T) {
+	notFound := PublicMessage{Key: "users.not_found", Message: "The user does not exist."}
+
+	t.Run("ToPublic rewrites matched errors", func(t *testing.T) {
+		err := HandleOr(fmt.Errorf("select: %w", ErrSentinel1),
+			ToPublic(DefaultPublicMessage),
+			OnSentinel(ErrSentinel1, ToPublic(notFound)),
+		)
+		require.ErrorIs(t, err, ErrSentinel1)
+		assert.Equal(t, notFound, Public(err))
+	})
+
+	t.Run("ToPublic as default handler", func(t *testing.T) {
+		err := HandleOr(io.EOF,
+			ToPublic(PublicMessage{Key: "fallback"}),
+			OnSentinel(ErrSentinel1, ToPublic(notFound)),
+		)
+		assert.Equal(t, "fallback", Public(err).Key)
+	})
+}
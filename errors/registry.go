@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Identity codes registered for the context package's sentinels, so that
+// OnCanceled and OnDeadlineExceeded still match errors rebuilt by
+// SerializedError.Err.
+const (
+	codeCanceled         = "context.canceled"
+	codeDeadlineExceeded = "context.deadline_exceeded"
+)
+
+// registry maps stable identity codes to sentinel errors and back.
+// It lets sentinels keep their identity across process boundaries.
+var registry = struct {
+	sync.RWMutex
+
+	byCode map[string]error
+	byErr  map[error]string
+	defs   map[string]Definition // Sentinels declared with Define, by code
+}{
+	byCode: map[string]error{
+		codeCanceled:         context.Canceled,
+		codeDeadlineExceeded: context.DeadlineExceeded,
+	},
+	byErr: map[error]string{
+		context.Canceled:         codeCanceled,
+		context.DeadlineExceeded: codeDeadlineExceeded,
+	},
+	defs: make(map[string]Definition),
+}
+
+// Register associates a stable identity code with a sentinel error and returns
+// the sentinel, so it can be used directly in a variable declaration.
+//
+// Registered sentinels are identified by their code when serialized with
+// Serialize, and errors rebuilt by SerializedError.Err match them with errors.Is
+// and OnSentinel, even in another process. context.Canceled and
+// context.DeadlineExceeded are registered as "context.canceled" and
+// "context.deadline_exceeded".
+//
+// Register panics if code is empty, if sentinel is nil or not comparable, or if
+// code is already registered for a different sentinel. It is intended to be
+// called during package initialization.
+//
+// Example:
+//
+//	var ErrNotFound = errors.Register("users.not_found", errors.New("user not found"))
+func Register(code string, sentinel error) error {
+	if code == "" {
+		panic("errors: Register called with empty code")
+	}
+	if sentinel == nil {
+		panic("errors: Register called with nil sentinel")
+	}
+	if !reflect.TypeOf(sentinel).Comparable() {
+		panic(fmt.Sprintf("errors: Register called with non-comparable sentinel of type %T", sentinel))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if existing, ok := registry.byCode[code]; ok && existing != sentinel { //nolint:errorlint // identity check
+		panic(fmt.Sprintf("errors: code %q is already registered", code))
+	}
+	registry.byCode[code] = sentinel
+	registry.byErr[sentinel] = code
+	return sentinel
+}
+
+// registeredCode returns the code err was registered with, if any.
+// Only err itself is considered, not its chain.
+func registeredCode(err error) (string, bool) {
+	if err == nil || !reflect.TypeOf(err).Comparable() {
+		return "", false
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+	code, ok := registry.byErr[err]
+	return code, ok
+}
+
+// codeOf returns the identity code of err itself: its registered code,
+// or the code it was deserialized with.
+func codeOf(err error) (string, bool) {
+	if re, ok := err.(interface{ identityCode() string }); ok {
+		code := re.identityCode()
+		return code, code != ""
+	}
+	return registeredCode(err)
+}
+
+// CodeOf returns the identity code of the first registered sentinel in err's tree.
+// Errors rebuilt by SerializedError.Err report the code they were serialized with.
+//
+// Example:
+//
+//	var ErrNotFound = errors.Register("users.not_found", errors.New("user not found"))
+//
+//	errors.CodeOf(fmt.Errorf("load: %w", ErrNotFound)) // "users.not_found", true
+func CodeOf(err error) (string, bool) {
+	var (
+		code  string
+		found bool
+	)
+	walk(err, func(e error) bool {
+		code, found = codeOf(e)
+		return !found
+	})
+	return code, found
+}
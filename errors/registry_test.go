@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uncomparableError cannot be used as a map key.
+type uncomparableError []string
+
+func (e uncomparableError) Error() string { return "uncomparable" }
+
+// unregister removes code and its sentinel from the registry, so that tests
+// registering new sentinels can run more than once in the same process.
+func unregister(code string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if sentinel, ok := registry.byCode[code]; ok {
+		delete(registry.byErr, sentinel)
+	}
+	delete(registry.byCode, code)
+	delete(registry.defs, code)
+}
+
+// registerTest registers sentinel with code until the end of the test.
+func registerTest(t *testing.T, code string, sentinel error) error {
+	t.Helper()
+	t.Cleanup(func() { unregister(code) })
+	return Register(code, sentinel)
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("Register returns sentinel", func(t *testing.T) {
+		sentinel := errors.New("registered")
+		assert.Same(t, sentinel, registerTest(t, "test.register.returns", sentinel))
+	})
+
+	t.Run("Register same sentinel twice", func(t *testing.T) {
+		sentinel := errors.New("registered twice")
+		registerTest(t, "test.register.twice", sentinel)
+		assert.NotPanics(t, func() {
+			Register("test.register.twice", sentinel)
+		})
+	})
+
+	t.Run("Register panics on invalid input", func(t *testing.T) {
+		assert.PanicsWithValue(t, "errors: Register called with empty code", func() {
+			Register("", io.EOF)
+		})
+		assert.PanicsWithValue(t, "errors: Register called with nil sentinel", func() {
+			Register("test.register.nil", nil)
+		})
+		assert.Panics(t, func() {
+			Register("test.register.uncomparable", uncomparableError{"a"})
+		})
+	})
+
+	t.Run("Register panics on duplicate code", func(t *testing.T) {
+		registerTest(t, "test.register.duplicate", errors.New("first"))
+		assert.PanicsWithValue(t, `errors: code "test.register.duplicate" is already registered`, func() {
+			Register("test.register.duplicate", errors.New("second"))
+		})
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	sentinel := registerTest(t, "test.codeof", errors.New("code of"))
+
+	t.Run("CodeOf registered sentinel", func(t *testing.T) {
+		code, ok := CodeOf(sentinel)
+		assert.True(t, ok)
+		assert.Equal(t, "test.codeof", code)
+	})
+
+	t.Run("CodeOf wrapped sentinel", func(t *testing.T) {
+		code, ok := CodeOf(errors.Join(io.EOF, fmt.Errorf("wrapped: %w", sentinel)))
+		assert.True(t, ok)
+		assert.Equal(t, "test.codeof", code)
+	})
+
+	t.Run("CodeOf unregistered error", func(t *testing.T) {
+		_, ok := CodeOf(fmt.Errorf("wrapped: %w", io.ErrClosedPipe))
+		assert.False(t, ok)
+	})
+
+	t.Run("CodeOf uncomparable error", func(t *testing.T) {
+		_, ok := CodeOf(uncomparableError{"a"})
+		assert.False(t, ok)
+	})
+
+	t.Run("CodeOf nil error", func(t *testing.T) {
+		_, ok := CodeOf(nil)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,482 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default values used by NewBatchReporter for zero fields of BatchReporterOptions.
+const (
+	defaultReporterBatchSize     = 100
+	defaultReporterFlushInterval = 10 * time.Second
+	defaultReporterQueueSize     = 1024
+	defaultReporterRateWindow    = time.Minute
+)
+
+// Reporter receives errors to report somewhere, such as a log or an error
+// tracking service. Report must not block and must be safe for concurrent use.
+type Reporter interface {
+	Report(err error)
+}
+
+// ReportHandler returns an ErrorHandler that reports every error to r and
+// returns it unchanged, for example as the default handler of HandleOr.
+//
+// Example:
+//
+//	result := errors.HandleOr(err, errors.ReportHandler(reporter),
+//	    errors.OnCanceled(func(e error) error { return nil }),
+//	)
+func ReportHandler(r Reporter) ErrorHandler {
+	return func(err error) error {
+		r.Report(err)
+		return err
+	}
+}
+
+// Report describes the occurrences of errors sharing a fingerprint within one
+// batch of a BatchReporter.
+type Report struct {
+	Fingerprint string    `json:"fingerprint"`
+	Type        string    `json:"type"`    // Type of the outermost error
+	Message     string    `json:"message"` // Message of the first occurrence
+	Count       int       `json:"count"`   // Occurrences merged into the report
+	FirstSeen   time.Time `json:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen"`
+	Err         error     `json:"-"` // First occurrence
+}
+
+// ReportSink receives the batches of a BatchReporter.
+// Send is never called concurrently by the same BatchReporter.
+type ReportSink interface {
+	Send(ctx context.Context, reports []Report) error
+}
+
+// ReportSinkFunc adapts a function to a ReportSink.
+type ReportSinkFunc func(ctx context.Context, reports []Report) error
+
+// Send calls f.
+func (f ReportSinkFunc) Send(ctx context.Context, reports []Report) error {
+	return f(ctx, reports)
+}
+
+// SlogSink returns a ReportSink that logs every report with logger at the given
+// level, with the report's fingerprint, type and count, and the error as LogValue.
+func SlogSink(logger *slog.Logger, level slog.Level) ReportSink {
+	return ReportSinkFunc(func(ctx context.Context, reports []Report) error {
+		for _, report := range reports {
+			logger.LogAttrs(ctx, level, "error reported",
+				slog.String("fingerprint", report.Fingerprint),
+				slog.String("type", report.Type),
+				slog.Int("count", report.Count),
+				slog.Any("err", LogValue(report.Err)),
+			)
+		}
+		return nil
+	})
+}
+
+// WriterSink returns a ReportSink that writes every report to w as a line of JSON.
+func WriterSink(w io.Writer) ReportSink {
+	encoder := json.NewEncoder(w)
+	return ReportSinkFunc(func(_ context.Context, reports []Report) error {
+		for _, report := range reports {
+			if err := encoder.Encode(report); err != nil {
+				return fmt.Errorf("write report: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ChanSink returns a ReportSink that sends every report on ch.
+// It blocks until the report is received or ctx is done.
+func ChanSink(ch chan<- Report) ReportSink {
+	return ReportSinkFunc(func(ctx context.Context, reports []Report) error {
+		for _, report := range reports {
+			select {
+			case ch <- report:
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}
+		return nil
+	})
+}
+
+// ReportRecorder is a ReportSink that keeps every report it receives, for tests.
+// The zero value is ready to use.
+type ReportRecorder struct {
+	mu      sync.Mutex
+	batches [][]Report
+}
+
+// Send records reports as one batch.
+func (r *ReportRecorder) Send(_ context.Context, reports []Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, slices.Clone(reports))
+	return nil
+}
+
+// Reports returns every report received, in order.
+func (r *ReportRecorder) Reports() []Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Concat(r.batches...)
+}
+
+// Batches returns the number of batches received.
+func (r *ReportRecorder) Batches() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches)
+}
+
+// BatchReporterOptions configures a BatchReporter.
+// Zero values select the defaults noted on each field.
+type BatchReporterOptions struct {
+	// Sinks receive every batch, in order.
+	Sinks []ReportSink
+	// BatchSize is the number of distinct fingerprints that triggers a flush.
+	// Defaults to 100.
+	BatchSize int
+	// FlushInterval is the longest time a report waits before being flushed.
+	// Defaults to 10s.
+	FlushInterval time.Duration
+	// QueueSize is the number of errors Report can queue before dropping new
+	// ones. Defaults to 1024.
+	QueueSize int
+	// RateLimit is the maximum number of reports per error type within
+	// RateWindow, where the type of an error is the type of the innermost
+	// errors of its tree, so that errors only sharing a wrapper such as Wrap or
+	// fmt.Errorf are limited separately. Errors over the limit are dropped,
+	// except those merged into a report of the current batch. Zero means no limit.
+	RateLimit int
+	// RateWindow is the window RateLimit applies to. Defaults to 1m.
+	RateWindow time.Duration
+	// OnError, if set, is called with the errors returned by the sinks during
+	// background flushes.
+	OnError func(error)
+	// Clock is used to timestamp errors and to schedule flushes.
+	// Nil means the system clock.
+	Clock Clock
+}
+
+// ReporterStats counts the errors a BatchReporter did not report.
+type ReporterStats struct {
+	Dropped     int64 // Errors dropped because the queue was full or the reporter closed
+	RateLimited int64 // Errors dropped because their type was over the rate limit
+}
+
+// reportItem is an error queued by Report.
+type reportItem struct {
+	err error
+	at  time.Time
+}
+
+// flushRequest asks the background goroutine of a BatchReporter to flush.
+type flushRequest struct {
+	ctx   context.Context //nolint:containedctx // carried to the goroutine doing the flush
+	reply chan error
+}
+
+// rateCounter counts the reports of one rate key within a rate window.
+type rateCounter struct {
+	start time.Time
+	count int
+}
+
+// BatchReporter is a Reporter that reports errors asynchronously, in batches.
+// Errors with the same Fingerprint within a batch are merged into one Report,
+// and each error type can be rate-limited. Batches are sent to the sinks when
+// they are full, every FlushInterval, on Flush and on Close.
+//
+// A BatchReporter is safe for concurrent use and must be created with
+// NewBatchReporter and closed with Close.
+type BatchReporter struct {
+	opts    BatchReporterOptions
+	cancel  context.CancelFunc // Cancels the context passed to the sinks
+	flushes chan flushRequest
+	done    chan struct{}
+
+	mu     sync.RWMutex // Guards closing queue
+	queue  chan reportItem
+	closed bool
+
+	dropped     atomic.Int64
+	rateLimited atomic.Int64
+
+	// Owned by the background goroutine.
+	pending []Report
+	index   map[string]int          // Fingerprint to index in pending
+	rates   map[string]*rateCounter // By rate key
+}
+
+// NewBatchReporter returns a BatchReporter and starts its background goroutine.
+//
+// Example:
+//
+//	reporter := errors.NewBatchReporter(errors.BatchReporterOptions{
+//	    Sinks:     []errors.ReportSink{errors.SlogSink(logger, slog.LevelError)},
+//	    RateLimit: 10,
+//	})
+//	defer reporter.Close(context.Background())
+//
+//	for job := range jobs {
+//	    if err := process(job); err != nil {
+//	        reporter.Report(err)
+//	    }
+//	}
+func NewBatchReporter(opts BatchReporterOptions) *BatchReporter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultReporterBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultReporterFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultReporterQueueSize
+	}
+	if opts.RateWindow <= 0 {
+		opts.RateWindow = defaultReporterRateWindow
+	}
+	opts.Clock = clockOrSystem(opts.Clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &BatchReporter{
+		opts:    opts,
+		cancel:  cancel,
+		flushes: make(chan flushRequest),
+		done:    make(chan struct{}),
+		queue:   make(chan reportItem, opts.QueueSize),
+		index:   make(map[string]int),
+		rates:   make(map[string]*rateCounter),
+	}
+	go r.run(ctx)
+	return r
+}
+
+// Report queues err to be reported. It never blocks: if the queue is full or
+// the reporter is closed, err is dropped and counted in Stats.
+// A nil err is ignored.
+func (r *BatchReporter) Report(err error) {
+	if err == nil {
+		return
+	}
+	item := reportItem{err: err, at: r.opts.Clock.Now()}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		r.dropped.Add(1)
+		return
+	}
+	select {
+	case r.queue <- item:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Flush sends the errors reported so far to the sinks and waits for them,
+// returning the sinks' errors joined with Join. It returns nil once the
+// reporter is closed, and ctx's cause if ctx is done first.
+func (r *BatchReporter) Flush(ctx context.Context) error {
+	req := flushRequest{ctx: ctx, reply: make(chan error, 1)}
+	select {
+	case r.flushes <- req:
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+
+	select {
+	case err := <-req.reply:
+		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Close stops accepting errors, sends the queued ones to the sinks and waits
+// for the background goroutine to finish. If ctx is done first, Close cancels
+// the context passed to the sinks and returns ctx's cause without waiting
+// further. Calling Close more than once is safe.
+func (r *BatchReporter) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return context.Cause(ctx)
+	}
+}
+
+// Stats returns the number of errors dropped so far.
+func (r *BatchReporter) Stats() ReporterStats {
+	return ReporterStats{
+		Dropped:     r.dropped.Load(),
+		RateLimited: r.rateLimited.Load(),
+	}
+}
+
+// run is the background goroutine. It owns the pending batch, and passes ctx
+// to the sinks except on Flush.
+func (r *BatchReporter) run(ctx context.Context) {
+	defer close(r.done)
+	defer r.cancel()
+
+	timer := r.opts.Clock.After(r.opts.FlushInterval)
+	for {
+		select {
+		case item, ok := <-r.queue:
+			if !ok {
+				r.notify(r.flush(ctx))
+				return
+			}
+			r.add(ctx, item)
+		case req := <-r.flushes:
+			r.drain(req.ctx)
+			req.reply <- r.flush(req.ctx)
+		case <-timer:
+			r.notify(r.flush(ctx))
+			timer = r.opts.Clock.After(r.opts.FlushInterval)
+		}
+	}
+}
+
+// drain adds the errors already in the queue to the pending batch.
+func (r *BatchReporter) drain(ctx context.Context) {
+	for {
+		select {
+		case item, ok := <-r.queue:
+			if !ok {
+				return
+			}
+			r.add(ctx, item)
+		default:
+			return
+		}
+	}
+}
+
+// add merges item into the pending batch, and flushes the batch once it is full.
+func (r *BatchReporter) add(ctx context.Context, item reportItem) {
+	fingerprint := Fingerprint(item.err)
+	if i, ok := r.index[fingerprint]; ok {
+		r.pending[i].Count++
+		r.pending[i].LastSeen = item.at
+		return
+	}
+
+	if !r.allow(rateKey(item.err), item.at) {
+		r.rateLimited.Add(1)
+		return
+	}
+
+	r.index[fingerprint] = len(r.pending)
+	r.pending = append(r.pending, Report{
+		Fingerprint: fingerprint,
+		Type:        typeName(item.err),
+		Message:     item.err.Error(),
+		Count:       1,
+		FirstSeen:   item.at,
+		LastSeen:    item.at,
+		Err:         item.err,
+	})
+	if len(r.pending) >= r.opts.BatchSize {
+		r.notify(r.flush(ctx))
+	}
+}
+
+// rateKey returns the key err is rate-limited under: the types of the innermost
+// errors of its tree, in order and without duplicates.
+func rateKey(err error) string {
+	var types []string
+	walk(err, func(e error) bool {
+		switch x := e.(type) {
+		case interface{ Unwrap() error }:
+			if x.Unwrap() != nil {
+				return true
+			}
+		case interface{ Unwrap() []error }:
+			if len(x.Unwrap()) > 0 {
+				return true
+			}
+		}
+		if name := typeName(e); !slices.Contains(types, name) {
+			types = append(types, name)
+		}
+		return true
+	})
+	return strings.Join(types, "+")
+}
+
+// allow reports whether a new report with the given rate key is within the
+// rate limit at now.
+func (r *BatchReporter) allow(key string, now time.Time) bool {
+	if r.opts.RateLimit <= 0 {
+		return true
+	}
+
+	counter, ok := r.rates[key]
+	if !ok || !now.Before(counter.start.Add(r.opts.RateWindow)) {
+		counter = &rateCounter{start: now}
+		r.rates[key] = counter
+	}
+	if counter.count >= r.opts.RateLimit {
+		return false
+	}
+	counter.count++
+	return true
+}
+
+// flush sends the pending batch to every sink and starts a new batch.
+// It returns the sinks' errors joined with Join.
+func (r *BatchReporter) flush(ctx context.Context) error {
+	if len(r.pending) == 0 {
+		return nil
+	}
+	batch := r.pending
+	r.pending = nil
+	clear(r.index)
+
+	now := r.opts.Clock.Now()
+	for key, counter := range r.rates {
+		if !now.Before(counter.start.Add(r.opts.RateWindow)) {
+			delete(r.rates, key)
+		}
+	}
+
+	var errs []error
+	for _, sink := range r.opts.Sinks {
+		if err := sink.Send(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return Join(errs...)
+}
+
+// notify passes err to OnError, if both are set.
+func (r *BatchReporter) notify(err error) {
+	if err != nil && r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
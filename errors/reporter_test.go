@@ -0,0 +1,343 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stoppedClock is a fakeClock whose timers never fire, so that a
+// BatchReporter only flushes when told to.
+type stoppedClock struct {
+	*fakeClock
+}
+
+func (stoppedClock) After(time.Duration) <-chan time.Time {
+	return nil
+}
+
+func newTestReporter(t *testing.T, opts BatchReporterOptions) (*BatchReporter, *ReportRecorder, *fakeClock) {
+	t.Helper()
+
+	clock := newFakeClock()
+	recorder := &ReportRecorder{}
+	opts.Sinks = append(opts.Sinks, recorder)
+	opts.Clock = stoppedClock{clock}
+	reporter := NewBatchReporter(opts)
+	t.Cleanup(func() { _ = reporter.Close(context.Background()) })
+	return reporter, recorder, clock
+}
+
+func TestBatchReporter(t *testing.T) {
+	t.Run("BatchReporter merges errors with the same fingerprint", func(t *testing.T) {
+		reporter, recorder, clock := newTestReporter(t, BatchReporterOptions{})
+
+		reporter.Report(fmt.Errorf("load user %d: %w", 1, io.EOF))
+		clock.Advance(time.Second)
+		reporter.Report(fmt.Errorf("load user %d: %w", 2, io.EOF))
+		reporter.Report(io.ErrUnexpectedEOF)
+		reporter.Report(nil)
+		require.NoError(t, reporter.Flush(t.Context()))
+
+		reports := recorder.Reports()
+		require.Len(t, reports, 2)
+		assert.Equal(t, "load user 1: EOF", reports[0].Message)
+		assert.Equal(t, "*fmt.wrapError", reports[0].Type)
+		assert.Equal(t, 2, reports[0].Count)
+		assert.Equal(t, time.Second, reports[0].LastSeen.Sub(reports[0].FirstSeen))
+		require.ErrorIs(t, reports[0].Err, io.EOF)
+		assert.Equal(t, 1, reports[1].Count)
+		assert.Equal(t, 1, recorder.Batches())
+	})
+
+	t.Run("BatchReporter starts a new batch after a flush", func(t *testing.T) {
+		reporter, recorder, _ := newTestReporter(t, BatchReporterOptions{})
+
+		reporter.Report(io.EOF)
+		require.NoError(t, reporter.Flush(t.Context()))
+		require.NoError(t, reporter.Flush(t.Context()))
+		reporter.Report(io.EOF)
+		require.NoError(t, reporter.Flush(t.Context()))
+
+		assert.Equal(t, 2, recorder.Batches())
+		assert.Len(t, recorder.Reports(), 2)
+	})
+
+	t.Run("BatchReporter flushes full batches", func(t *testing.T) {
+		reporter, recorder, _ := newTestReporter(t, BatchReporterOptions{BatchSize: 2})
+
+		reporter.Report(io.EOF)
+		reporter.Report(io.ErrUnexpectedEOF)
+		reporter.Report(io.ErrClosedPipe)
+		require.NoError(t, reporter.Flush(t.Context()))
+
+		assert.Equal(t, 2, recorder.Batches())
+		assert.Len(t, recorder.Reports(), 3)
+	})
+
+	t.Run("BatchReporter flushes on interval", func(t *testing.T) {
+		recorder := &ReportRecorder{}
+		reporter := NewBatchReporter(BatchReporterOptions{
+			Sinks:         []ReportSink{recorder},
+			FlushInterval: time.Millisecond,
+		})
+		defer func() { _ = reporter.Close(t.Context()) }()
+
+		reporter.Report(io.EOF)
+		assert.Eventually(t, func() bool {
+			return len(recorder.Reports()) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("BatchReporter rate-limits per type", func(t *testing.T) {
+		reporter, recorder, clock := newTestReporter(t, BatchReporterOptions{RateLimit: 2, RateWindow: time.Minute})
+
+		for i := range 4 {
+			reporter.Report(New(fmt.Sprintf("failure %c", 'a'+i)))
+		}
+		reporter.Report(io.EOF) // Another type
+		reporter.Report(New("failure a"))
+		reporter.Report(&ValidationError{Field: "name"})
+		require.NoError(t, reporter.Flush(t.Context()))
+
+		clock.Advance(time.Minute)
+		reporter.Report(New("failure e"))
+		require.NoError(t, reporter.Flush(t.Context()))
+
+		var messages []string
+		for _, report := range recorder.Reports() {
+			messages = append(messages, report.Message)
+		}
+		assert.Equal(t, []string{"failure a", "failure b", "EOF", "validation failed for field name with value ", "failure e"}, messages)
+		assert.Equal(t, 2, recorder.Reports()[0].Count)
+		assert.Equal(t, ReporterStats{RateLimited: 2}, reporter.Stats())
+	})
+
+	t.Run("BatchReporter rate-limits per innermost type", func(t *testing.T) {
+		reporter, recorder, _ := newTestReporter(t, BatchReporterOptions{RateLimit: 1})
+
+		reporter.Report(fmt.Errorf("read: %w", io.EOF))
+		reporter.Report(Wrap(fmt.Errorf("validate: %w", &ValidationError{Field: "name"}), "handle"))
+		reporter.Report(fmt.Errorf("write: %w", io.ErrClosedPipe))
+		reporter.Report(With(New("failure"), "id", 1))
+		require.NoError(t, reporter.Flush(t.Context()))
+
+		var messages []string
+		for _, report := range recorder.Reports() {
+			messages = append(messages, report.Message)
+		}
+		assert.Equal(t, []string{"read: EOF", "handle: validate: validation failed for field name with value ", "failure"}, messages)
+		assert.Equal(t, ReporterStats{RateLimited: 1}, reporter.Stats())
+	})
+
+	t.Run("BatchReporter drops errors when the queue is full", func(t *testing.T) {
+		block := make(chan struct{})
+		reporter := NewBatchReporter(BatchReporterOptions{
+			Sinks: []ReportSink{ReportSinkFunc(func(context.Context, []Report) error {
+				<-block
+				return nil
+			})},
+			BatchSize: 1,
+			QueueSize: 1,
+			Clock:     stoppedClock{newFakeClock()},
+		})
+
+		reporter.Report(io.EOF)
+		assert.Eventually(t, func() bool {
+			reporter.Report(io.ErrUnexpectedEOF)
+			return reporter.Stats().Dropped > 0
+		}, time.Second, time.Millisecond)
+
+		close(block)
+		require.NoError(t, reporter.Close(t.Context()))
+	})
+
+	t.Run("BatchReporter reports sink errors", func(t *testing.T) {
+		errSink := New("sink down")
+		var (
+			mu   sync.Mutex
+			errs []error
+		)
+		reporter := NewBatchReporter(BatchReporterOptions{
+			Sinks: []ReportSink{ReportSinkFunc(func(context.Context, []Report) error {
+				return errSink
+			})},
+			OnError: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			},
+			Clock: stoppedClock{newFakeClock()},
+		})
+
+		reporter.Report(io.EOF)
+		require.ErrorIs(t, reporter.Flush(t.Context()), errSink)
+
+		reporter.Report(io.EOF)
+		require.NoError(t, reporter.Close(t.Context()))
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, errs, 1)
+		require.ErrorIs(t, errs[0], errSink)
+	})
+
+	t.Run("BatchReporter drains on Close", func(t *testing.T) {
+		recorder := &ReportRecorder{}
+		reporter := NewBatchReporter(BatchReporterOptions{
+			Sinks: []ReportSink{recorder},
+			Clock: stoppedClock{newFakeClock()},
+		})
+
+		for range 100 {
+			reporter.Report(io.EOF)
+		}
+		require.NoError(t, reporter.Close(t.Context()))
+		require.NoError(t, reporter.Close(t.Context()))
+
+		reports := recorder.Reports()
+		require.Len(t, reports, 1)
+		assert.Equal(t, 100, reports[0].Count)
+
+		reporter.Report(io.EOF)
+		assert.Equal(t, ReporterStats{Dropped: 1}, reporter.Stats())
+		require.NoError(t, reporter.Flush(t.Context()))
+	})
+
+	t.Run("BatchReporter Close gives up when context is done", func(t *testing.T) {
+		var sinkCtx context.Context
+		started := make(chan struct{})
+		reporter := NewBatchReporter(BatchReporterOptions{
+			Sinks: []ReportSink{ReportSinkFunc(func(ctx context.Context, _ []Report) error {
+				sinkCtx = ctx
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			})},
+			Clock: stoppedClock{newFakeClock()},
+		})
+
+		reporter.Report(io.EOF)
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, reporter.Close(ctx), context.DeadlineExceeded)
+
+		<-started
+		<-reporter.done
+		require.ErrorIs(t, sinkCtx.Err(), context.Canceled)
+	})
+
+	t.Run("BatchReporter is safe for concurrent use", func(t *testing.T) {
+		reporter, recorder, _ := newTestReporter(t, BatchReporterOptions{BatchSize: 3, QueueSize: 10000})
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() {
+				for j := range 100 {
+					reporter.Report(fmt.Errorf("worker %d: %w", i, New(fmt.Sprintf("failure %c", 'a'+j%5))))
+				}
+			})
+		}
+		wg.Wait()
+		require.NoError(t, reporter.Close(t.Context()))
+
+		total := 0
+		for _, report := range recorder.Reports() {
+			total += report.Count
+		}
+		assert.Equal(t, 800, total)
+	})
+}
+
+func TestReportHandler(t *testing.T) {
+	reporter, recorder, _ := newTestReporter(t, BatchReporterOptions{})
+
+	result := HandleOr(io.EOF, ReportHandler(reporter),
+		OnSentinel(io.ErrUnexpectedEOF, func(error) error { return nil }),
+	)
+	require.ErrorIs(t, result, io.EOF)
+	assert.NoError(t, HandleOr(io.ErrUnexpectedEOF, ReportHandler(reporter),
+		OnSentinel(io.ErrUnexpectedEOF, func(error) error { return nil }),
+	))
+
+	require.NoError(t, reporter.Flush(t.Context()))
+	reports := recorder.Reports()
+	require.Len(t, reports, 1)
+	require.ErrorIs(t, reports[0].Err, io.EOF)
+}
+
+func TestReportSinks(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reports := []Report{
+		{Fingerprint: "abc", Type: "*errors.errorString", Message: "EOF", Count: 2, FirstSeen: at, LastSeen: at, Err: io.EOF},
+		{Fingerprint: "def", Type: "*errors.errorString", Message: "unexpected EOF", Count: 1, FirstSeen: at, LastSeen: at, Err: io.ErrUnexpectedEOF},
+	}
+
+	t.Run("SlogSink logs every report", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+
+		require.NoError(t, SlogSink(logger, slog.LevelWarn).Send(t.Context(), reports))
+		assert.Equal(t,
+			"level=WARN msg=\"error reported\" fingerprint=abc type=*errors.errorString count=2 err=EOF\n"+
+				"level=WARN msg=\"error reported\" fingerprint=def type=*errors.errorString count=1 err=\"unexpected EOF\"\n",
+			buf.String())
+	})
+
+	t.Run("WriterSink writes JSON lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriterSink(&buf).Send(t.Context(), reports))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.JSONEq(t,
+			`{"fingerprint":"abc","type":"*errors.errorString","message":"EOF","count":2,`+
+				`"firstSeen":"2025-01-01T00:00:00Z","lastSeen":"2025-01-01T00:00:00Z"}`,
+			lines[0])
+
+		var decoded Report
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+		assert.Equal(t, "unexpected EOF", decoded.Message)
+	})
+
+	t.Run("WriterSink with failing writer", func(t *testing.T) {
+		err := WriterSink(failingWriter{}).Send(t.Context(), reports)
+		require.ErrorIs(t, err, io.ErrClosedPipe)
+	})
+
+	t.Run("ChanSink sends every report", func(t *testing.T) {
+		ch := make(chan Report, 2)
+		require.NoError(t, ChanSink(ch).Send(t.Context(), reports))
+		assert.Equal(t, "abc", (<-ch).Fingerprint)
+		assert.Equal(t, "def", (<-ch).Fingerprint)
+	})
+
+	t.Run("ChanSink stops when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		err := ChanSink(make(chan Report)).Send(ctx, reports)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
@@ -0,0 +1,164 @@
+package errors
+
+import "fmt"
+
+// Result holds either a value or an error. It is an alternative to the
+// (T, error) tuple for pipelines where results are stored, sent through
+// channels or composed step by step.
+//
+// The zero Result is a success holding the zero value of T.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Success returns a successful Result holding value.
+//
+// Example:
+//
+//	results <- errors.Success(user)
+func Success[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Failure returns a failed Result holding err.
+// If err is nil, the Result is a success holding the zero value of T.
+//
+// Example:
+//
+//	results <- errors.Failure[*User](ErrNotFound)
+func Failure[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf returns a Result from a (T, error) tuple: a failure if err is not
+// nil, and a success holding value otherwise.
+//
+// Example:
+//
+//	results <- errors.ResultOf(loadUser(ctx, id))
+func ResultOf[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Failure[T](err)
+	}
+	return Success(value)
+}
+
+// Get returns the Result as a (T, error) tuple.
+// The value is the zero value of T if the Result is a failure.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the error of a failed Result, or nil.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Failed reports whether the Result holds an error.
+func (r Result[T]) Failed() bool {
+	return r.err != nil
+}
+
+// Unwrap returns the value of a successful Result.
+// It panics with an error wrapping the Result's error if the Result is a failure.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Errorf("unwrap failed result: %w", r.err))
+	}
+	return r.value
+}
+
+// UnwrapOr returns the value of a successful Result, or fallback if it is a failure.
+func (r Result[T]) UnwrapOr(fallback T) T {
+	if r.err != nil {
+		return fallback
+	}
+	return r.value
+}
+
+// OrElse returns the Result unchanged if it is a success, and the result of
+// calling fn with the error otherwise.
+//
+// Example:
+//
+//	user := errors.ResultOf(cache.Get(id)).OrElse(func(err error) errors.Result[*User] {
+//	    return errors.ResultOf(db.LoadUser(ctx, id))
+//	})
+func (r Result[T]) OrElse(fn func(error) Result[T]) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	return fn(r.err)
+}
+
+// Handle sends the error of a failed Result through the matchers, as Handle
+// would, and returns a Result holding the handler's result instead. If the
+// handler returns nil, the Result becomes a success holding the zero value of T.
+// Successful Results and errors no matcher matches are returned unchanged.
+//
+// Example:
+//
+//	result = result.Handle(
+//	    errors.OnSentinel(sql.ErrNoRows, func(e error) error {
+//	        return ErrUserNotFound
+//	    }),
+//	)
+func (r Result[T]) Handle(matchers ...ErrorMatcher) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	if handled, result := HandleError(r.err, matchers...); handled {
+		return Failure[T](result)
+	}
+	return r
+}
+
+// Recover turns the error of a failed Result into a value with the first
+// matching case, as Match would, and returns a successful Result holding it.
+// Successful Results and errors no case matches are returned unchanged.
+//
+// Example:
+//
+//	count := errors.ResultOf(countVisits(ctx)).Recover(
+//	    errors.Case(ErrNoData, func(e error) int { return 0 }),
+//	)
+func (r Result[T]) Recover(cases ...MatchCase[T]) Result[T] {
+	if r.err == nil {
+		return r
+	}
+	if value, ok := Match(r.err, cases...); ok {
+		return Success(value)
+	}
+	return r
+}
+
+// Map returns a Result holding fn applied to the value of r if r is a success,
+// or a failure holding the error of r otherwise.
+//
+// Example:
+//
+//	names := errors.Map(errors.ResultOf(loadUser(ctx, id)), func(u *User) string {
+//	    return u.Name
+//	})
+func Map[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Failure[U](r.err)
+	}
+	return Success(fn(r.value))
+}
+
+// AndThen returns the result of calling fn with the value of r if r is a success,
+// or a failure holding the error of r otherwise. It chains steps that can fail.
+//
+// Example:
+//
+//	orders := errors.AndThen(errors.ResultOf(loadUser(ctx, id)), func(u *User) errors.Result[[]Order] {
+//	    return errors.ResultOf(loadOrders(ctx, u.ID))
+//	})
+func AndThen[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Failure[U](r.err)
+	}
+	return fn(r.value)
+}
@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultConstructors(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		r := Success(42)
+		value, err := r.Get()
+		assert.Equal(t, 42, value)
+		require.NoError(t, err)
+		assert.False(t, r.Failed())
+		assert.NoError(t, r.Err())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		r := Failure[int](io.EOF)
+		value, err := r.Get()
+		assert.Zero(t, value)
+		require.ErrorIs(t, err, io.EOF)
+		assert.True(t, r.Failed())
+	})
+
+	t.Run("Failure with nil error", func(t *testing.T) {
+		assert.False(t, Failure[int](nil).Failed())
+	})
+
+	t.Run("ResultOf", func(t *testing.T) {
+		assert.Equal(t, Success(7), ResultOf(strconv.Atoi("7")))
+
+		r := ResultOf(strconv.Atoi("x"))
+		assert.True(t, r.Failed())
+		var numErr *strconv.NumError
+		require.ErrorAs(t, r.Err(), &numErr)
+	})
+
+	t.Run("Result zero value", func(t *testing.T) {
+		var r Result[string]
+		assert.False(t, r.Failed())
+		assert.Empty(t, r.Unwrap())
+	})
+}
+
+func TestResultUnwrap(t *testing.T) {
+	assert.Equal(t, 1, Success(1).Unwrap())
+	assert.PanicsWithError(t, "unwrap failed result: EOF", func() {
+		Failure[int](io.EOF).Unwrap()
+	})
+
+	assert.Equal(t, 1, Success(1).UnwrapOr(2))
+	assert.Equal(t, 2, Failure[int](io.EOF).UnwrapOr(2))
+}
+
+func TestResultOrElse(t *testing.T) {
+	calls := 0
+	fallback := func(err error) Result[int] {
+		calls++
+		return Success(len(err.Error()))
+	}
+
+	assert.Equal(t, Success(1), Success(1).OrElse(fallback))
+	assert.Zero(t, calls)
+
+	assert.Equal(t, Success(3), Failure[int](io.EOF).OrElse(fallback))
+	assert.Equal(t, 1, calls)
+}
+
+func TestResultHandle(t *testing.T) {
+	matchers := []ErrorMatcher{
+		OnSentinel(io.EOF, func(e error) error { return nil }),
+		OnType(func(e *ValidationError) error { return fmt.Errorf("invalid %s", e.Field) }),
+	}
+
+	t.Run("Result Handle with success", func(t *testing.T) {
+		assert.Equal(t, Success(1), Success(1).Handle(matchers...))
+	})
+
+	t.Run("Result Handle recovers into the zero value", func(t *testing.T) {
+		r := Failure[int](fmt.Errorf("read: %w", io.EOF)).Handle(matchers...)
+		assert.False(t, r.Failed())
+		assert.Zero(t, r.Unwrap())
+	})
+
+	t.Run("Result Handle replaces the error", func(t *testing.T) {
+		r := Failure[int](&ValidationError{Field: "age"}).Handle(matchers...)
+		require.EqualError(t, r.Err(), "invalid age")
+	})
+
+	t.Run("Result Handle with unmatched error", func(t *testing.T) {
+		r := Failure[int](io.ErrUnexpectedEOF).Handle(matchers...)
+		require.ErrorIs(t, r.Err(), io.ErrUnexpectedEOF)
+	})
+}
+
+func TestResultRecover(t *testing.T) {
+	cases := []MatchCase[int]{
+		Case(io.EOF, func(e error) int { return 0 }),
+		CaseType(func(e *ValidationError) int { return -1 }),
+	}
+
+	assert.Equal(t, Success(5), Success(5).Recover(cases...))
+	assert.Equal(t, Success(0), Failure[int](io.EOF).Recover(cases...))
+	assert.Equal(t, Success(-1), Failure[int](&ValidationError{}).Recover(cases...))
+
+	r := Failure[int](io.ErrUnexpectedEOF).Recover(cases...)
+	require.ErrorIs(t, r.Err(), io.ErrUnexpectedEOF)
+}
+
+func TestResultComposition(t *testing.T) {
+	parse := func(s string) Result[int] { return ResultOf(strconv.Atoi(s)) }
+	half := func(n int) Result[int] {
+		if n%2 != 0 {
+			return Failure[int](errors.New("odd"))
+		}
+		return Success(n / 2)
+	}
+
+	t.Run("Map", func(t *testing.T) {
+		assert.Equal(t, Success("42!"), Map(Success(42), func(n int) string { return strconv.Itoa(n) + "!" }))
+
+		r := Map(Failure[int](io.EOF), func(n int) string { return "unused" })
+		require.ErrorIs(t, r.Err(), io.EOF)
+	})
+
+	t.Run("AndThen", func(t *testing.T) {
+		assert.Equal(t, Success(21), AndThen(parse("42"), half))
+		require.EqualError(t, AndThen(parse("7"), half).Err(), "odd")
+
+		calls := 0
+		r := AndThen(parse("x"), func(n int) Result[int] {
+			calls++
+			return Success(n)
+		})
+		assert.True(t, r.Failed())
+		assert.Zero(t, calls)
+	})
+
+	t.Run("Result through a channel", func(t *testing.T) {
+		results := make(chan Result[int], 3)
+		for _, s := range []string{"4", "x", "6"} {
+			results <- AndThen(parse(s), half)
+		}
+		close(results)
+
+		var sum int
+		for r := range results {
+			sum += r.Recover(CaseType(func(e *strconv.NumError) int { return 100 })).UnwrapOr(0)
+		}
+		assert.Equal(t, 105, sum)
+	})
+}
@@ -0,0 +1,243 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Default values used by Retry for zero fields of RetryPolicy.
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryInitial    = 100 * time.Millisecond
+	defaultRetryMaxDelay   = 30 * time.Second
+	defaultRetryMultiplier = 2
+)
+
+// RetryClass tells Retry how to proceed after an attempt fails.
+type RetryClass int
+
+const (
+	// Retryable errors are retried with exponential backoff.
+	Retryable RetryClass = iota
+	// Permanent errors stop the retry loop immediately.
+	Permanent
+	// RateLimited errors are retried after the RetryAfter hint carried by the
+	// error, or after the policy's MaxDelay if there is none.
+	RateLimited
+)
+
+// RetryPolicy configures Retry.
+// Zero values select the defaults noted on each field.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, including the first one.
+	// Defaults to 3.
+	MaxAttempts int
+	// MaxElapsed stops retrying when the next attempt would start later than
+	// MaxElapsed after the first one. Zero means no limit.
+	MaxElapsed time.Duration
+	// InitialDelay is the delay before the first retry. Defaults to 100ms.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts. Defaults to 30s.
+	MaxDelay time.Duration
+	// Multiplier is the factor the delay grows by after each retry. Defaults to 2.
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction, in [0, 1].
+	// Zero disables jitter.
+	Jitter float64
+	// Rules classify errors. The first matching case wins. Errors no case
+	// matches are Permanent if they are marked as not retryable, as reported by
+	// a RetryableError in their tree, and Retryable otherwise.
+	Rules []MatchCase[RetryClass]
+	// Clock is used to measure elapsed time and to wait between attempts.
+	// Nil means the system clock.
+	Clock Clock
+	// Rand is the source of jitter. Nil means the global math/rand/v2 source.
+	Rand *rand.Rand
+}
+
+// withDefaults returns a copy of the policy with zero fields set to their defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryAttempts
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = defaultRetryInitial
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryMaxDelay
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+	p.Jitter = min(max(p.Jitter, 0), 1)
+	p.Clock = clockOrSystem(p.Clock)
+	return p
+}
+
+// classify returns the RetryClass of err according to the policy's rules.
+func (p RetryPolicy) classify(err error) RetryClass {
+	if class, ok := Match(err, p.Rules...); ok {
+		return class
+	}
+	if retryable, ok := behaviorOf(err, RetryableError.Retryable); ok && !retryable {
+		return Permanent
+	}
+	return Retryable
+}
+
+// jitter randomly shortens d by up to the policy's Jitter fraction.
+func (p RetryPolicy) jitter(d time.Duration) time.Duration {
+	if p.Jitter == 0 {
+		return d
+	}
+
+	r := rand.Float64 //nolint:gosec // jitter does not need a cryptographic source
+	if p.Rand != nil {
+		r = p.Rand.Float64
+	}
+	return d - time.Duration(float64(d)*p.Jitter*r())
+}
+
+// Retry calls fn until it succeeds, returns a permanent error, or the policy's
+// limits are reached, and returns the last error.
+//
+// Each failure is classified by the policy's Rules, or, if no rule matches,
+// by whether it is marked as retryable (see WithRetryable):
+//   - Retryable errors are retried after an exponentially growing delay,
+//     starting at InitialDelay and capped at MaxDelay, with optional jitter.
+//   - Permanent errors are returned immediately.
+//   - RateLimited errors are retried after the RetryAfter hint carried by the
+//     error, or after MaxDelay if there is none.
+//
+// A RetryAfter hint on a Retryable error is honored when it is longer than the
+// computed delay. If ctx is done before or between attempts, Retry stops and
+// returns an error that matches both the context's cause and the last error.
+//
+// Example:
+//
+//	err := errors.Retry(ctx, func(ctx context.Context) error {
+//	    return client.Send(ctx, msg)
+//	}, errors.RetryPolicy{
+//	    MaxAttempts: 5,
+//	    MaxElapsed:  time.Minute,
+//	    Jitter:      0.2,
+//	    Rules: []errors.MatchCase[errors.RetryClass]{
+//	        errors.CaseType(func(e *ValidationError) errors.RetryClass {
+//	            return errors.Permanent
+//	        }),
+//	        errors.Case(ErrTooManyRequests, func(e error) errors.RetryClass {
+//	            return errors.RateLimited
+//	        }),
+//	    },
+//	})
+func Retry(ctx context.Context, fn func(context.Context) error, policy RetryPolicy) error {
+	p := policy.withDefaults()
+	start := p.Clock.Now()
+	delay := p.InitialDelay
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil {
+			return retryAborted(ctx, lastErr)
+		}
+
+		lastErr = fn(ctx)
+		if lastErr == nil {
+			return nil
+		}
+
+		class := p.classify(lastErr)
+		if class == Permanent || attempt >= p.MaxAttempts {
+			return lastErr
+		}
+
+		var wait time.Duration
+		hint, hasHint := RetryAfter(lastErr)
+		switch {
+		case class == RateLimited && hasHint:
+			wait = hint
+		case class == RateLimited:
+			wait = p.MaxDelay
+		default:
+			wait = p.jitter(delay)
+			if hasHint && hint > wait {
+				wait = hint
+			}
+			delay = min(time.Duration(float64(delay)*p.Multiplier), p.MaxDelay)
+		}
+
+		if p.MaxElapsed > 0 && p.Clock.Now().Add(wait).Sub(start) > p.MaxElapsed {
+			return lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return retryAborted(ctx, lastErr)
+		case <-p.Clock.After(wait):
+		}
+	}
+}
+
+// retryAborted returns the error reported when ctx stops Retry.
+// It matches both the context's cause and the last attempt's error, if any.
+func retryAborted(ctx context.Context, lastErr error) error {
+	cause := context.Cause(ctx)
+	if lastErr == nil {
+		return cause
+	}
+	return fmt.Errorf("retry aborted: %w: %w", cause, lastErr)
+}
+
+// retryAfterError is an error annotated with a retry delay hint.
+type retryAfterError struct {
+	wrapper
+
+	delay time.Duration
+}
+
+// RetryAfter returns the delay hint attached to the error.
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.delay
+}
+
+// WithRetryAfter attaches a hint telling Retry to wait at least d before the
+// next attempt, typically taken from a Retry-After header.
+// If err is nil, WithRetryAfter returns nil.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusTooManyRequests {
+//	    return errors.WithRetryAfter(ErrTooManyRequests, 30*time.Second)
+//	}
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{wrapper{err}, d}
+}
+
+// RetryAfter returns the delay hint carried by err, looking through the whole
+// wrap chain for an error implementing a RetryAfter() time.Duration method.
+// The outermost hint wins.
+//
+// Example:
+//
+//	if d, ok := errors.RetryAfter(err); ok {
+//	    w.Header().Set("Retry-After", strconv.Itoa(int(d.Seconds())))
+//	}
+func RetryAfter(err error) (time.Duration, bool) {
+	var (
+		delay time.Duration
+		found bool
+	)
+	walk(err, func(e error) bool {
+		if ra, ok := e.(interface{ RetryAfter() time.Duration }); ok {
+			delay, found = ra.RetryAfter(), true
+			return false
+		}
+		return true
+	})
+	return delay, found
+}
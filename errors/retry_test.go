@@ -0,0 +1,263 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingFunc returns a function that fails with the given errors in order
+// and succeeds once they are used up. It records the number of calls.
+func failingFunc(calls *int, errs ...error) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("Retry succeeds on first attempt", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls), RetryPolicy{Clock: clock})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, clock.Sleeps())
+	})
+
+	t.Run("Retry with exponential backoff", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, io.EOF, io.EOF), RetryPolicy{
+			MaxAttempts:  5,
+			InitialDelay: 100 * time.Millisecond,
+			Clock:        clock,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, []time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+			400 * time.Millisecond,
+		}, clock.Sleeps())
+	})
+
+	t.Run("Retry caps delay at MaxDelay", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, io.EOF, io.EOF), RetryPolicy{
+			MaxAttempts:  4,
+			InitialDelay: time.Second,
+			MaxDelay:     3 * time.Second,
+			Multiplier:   4,
+			Clock:        clock,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, 3 * time.Second, 3 * time.Second}, clock.Sleeps())
+	})
+
+	t.Run("Retry returns last error after max attempts", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		lastErr := errors.New("last")
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, io.EOF, lastErr, io.EOF), RetryPolicy{
+			Clock: clock,
+		})
+
+		assert.Same(t, lastErr, err)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, clock.Sleeps(), 2)
+	})
+
+	t.Run("Retry stops on permanent error", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		validationErr := &ValidationError{Field: "email"}
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, validationErr, io.EOF), RetryPolicy{
+			MaxAttempts: 10,
+			Clock:       clock,
+			Rules: []MatchCase[RetryClass]{
+				CaseType(func(e *ValidationError) RetryClass { return Permanent }),
+			},
+		})
+
+		assert.Same(t, validationErr, err)
+		assert.Equal(t, 2, calls)
+		assert.Len(t, clock.Sleeps(), 1)
+	})
+
+	t.Run("Retry waits for RetryAfter hint when rate limited", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		errRateLimited := errors.New("too many requests")
+		err := Retry(t.Context(), failingFunc(&calls,
+			WithRetryAfter(errRateLimited, 7*time.Second),
+			errRateLimited,
+		), RetryPolicy{
+			MaxDelay: 20 * time.Second,
+			Clock:    clock,
+			Rules: []MatchCase[RetryClass]{
+				Case(errRateLimited, func(e error) RetryClass { return RateLimited }),
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{7 * time.Second, 20 * time.Second}, clock.Sleeps())
+	})
+
+	t.Run("Retry honors longer RetryAfter hint on retryable errors", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls,
+			WithRetryAfter(io.EOF, 5*time.Second),
+			WithRetryAfter(io.EOF, time.Millisecond),
+		), RetryPolicy{
+			InitialDelay: time.Second,
+			Clock:        clock,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{5 * time.Second, 2 * time.Second}, clock.Sleeps())
+	})
+
+	t.Run("Retry stops at MaxElapsed", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, io.EOF, io.EOF, io.EOF), RetryPolicy{
+			MaxAttempts:  10,
+			MaxElapsed:   time.Second,
+			InitialDelay: 300 * time.Millisecond,
+			Clock:        clock,
+		})
+
+		require.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{300 * time.Millisecond, 600 * time.Millisecond}, clock.Sleeps())
+	})
+
+	t.Run("Retry applies jitter", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, io.EOF, io.EOF), RetryPolicy{
+			MaxAttempts:  4,
+			InitialDelay: time.Second,
+			Jitter:       0.5,
+			Clock:        clock,
+			Rand:         rand.New(rand.NewPCG(1, 2)),
+		})
+
+		require.NoError(t, err)
+		base := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+		sleeps := clock.Sleeps()
+		require.Len(t, sleeps, len(base))
+		for i, sleep := range sleeps {
+			assert.LessOrEqual(t, sleep, base[i])
+			assert.GreaterOrEqual(t, sleep, base[i]/2)
+		}
+	})
+
+	t.Run("Retry stops on errors marked as not retryable", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls, io.EOF, WithRetryable(io.ErrUnexpectedEOF, false)), RetryPolicy{
+			MaxAttempts: 5,
+			Clock:       clock,
+		})
+
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Retry rules take precedence over retryable marks", func(t *testing.T) {
+		clock := newFakeClock()
+		calls := 0
+		err := Retry(t.Context(), failingFunc(&calls, WithRetryable(io.EOF, false)), RetryPolicy{
+			Clock: clock,
+			Rules: []MatchCase[RetryClass]{
+				Case(io.EOF, func(error) RetryClass { return Retryable }),
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Retry with already canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		calls := 0
+		err := Retry(ctx, failingFunc(&calls), RetryPolicy{Clock: newFakeClock()})
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("Retry stops when context is canceled between attempts", func(t *testing.T) {
+		errStop := errors.New("shutting down")
+		ctx, cancel := context.WithCancelCause(t.Context())
+
+		calls := 0
+		err := Retry(ctx, func(context.Context) error {
+			calls++
+			cancel(errStop)
+			return io.EOF
+		}, RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour})
+
+		require.ErrorIs(t, err, errStop)
+		require.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Retry passes context to fn", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(t.Context(), ctxKey{}, "value")
+
+		err := Retry(ctx, func(ctx context.Context) error {
+			assert.Equal(t, "value", ctx.Value(ctxKey{}))
+			return nil
+		}, RetryPolicy{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("WithRetryAfter nil error", func(t *testing.T) {
+		assert.NoError(t, WithRetryAfter(nil, time.Second))
+	})
+
+	t.Run("RetryAfter without hint", func(t *testing.T) {
+		_, ok := RetryAfter(io.EOF)
+		assert.False(t, ok)
+	})
+
+	t.Run("RetryAfter through wrap chain", func(t *testing.T) {
+		err := fmt.Errorf("call: %w", WithRetryAfter(io.EOF, 3*time.Second))
+		delay, ok := RetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, 3*time.Second, delay)
+		assert.EqualError(t, err, "call: EOF")
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("RetryAfter outermost hint wins", func(t *testing.T) {
+		err := WithRetryAfter(WithRetryAfter(io.EOF, time.Second), time.Minute)
+		delay, ok := RetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, delay)
+	})
+}
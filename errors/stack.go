@@ -0,0 +1,212 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames recorded for a single error.
+const maxStackDepth = 32
+
+// stackTracesDisabled turns off stack capture when set.
+// The zero value keeps stack traces enabled.
+var stackTracesDisabled atomic.Bool
+
+// SetStackTraces enables or disables stack capture for errors created by New,
+// Wrap and Wrapf. Stack traces are enabled by default.
+//
+// Disabling stack traces removes the cost of walking the call stack on every
+// error, which can be useful on hot paths where errors are expected. Errors
+// created while stack traces are disabled simply carry no trace.
+// It is safe to call SetStackTraces concurrently with error creation.
+//
+// Example:
+//
+//	errors.SetStackTraces(os.Getenv("ERROR_STACKS") != "off")
+func SetStackTraces(enabled bool) {
+	stackTracesDisabled.Store(!enabled)
+}
+
+// stack is a list of program counters of a captured call stack.
+type stack []uintptr
+
+// callers captures the current call stack, skipping the given number of frames.
+// It returns nil if stack traces are disabled.
+func callers(skip int) stack {
+	if stackTracesDisabled.Load() {
+		return nil
+	}
+
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+	return append(stack(nil), pcs[:n]...)
+}
+
+// frames resolves the program counters into function, file and line information.
+func (s stack) frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	result := make([]runtime.Frame, 0, len(s))
+	frames := runtime.CallersFrames(s)
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// formatFrames writes frames in the verbose %+v layout, one frame per two lines.
+func formatFrames(state fmt.State, frames []runtime.Frame) {
+	for _, frame := range frames {
+		fmt.Fprintf(state, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+}
+
+// stackError is an error annotated with a message and the stack at its creation.
+type stackError struct {
+	msg   string
+	cause error
+	stack stack
+}
+
+func (e *stackError) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.cause
+}
+
+// StackTrace returns the call stack recorded when the error was created,
+// or nil if stack traces were disabled at that time.
+func (e *stackError) StackTrace() []runtime.Frame {
+	return e.stack.frames()
+}
+
+// LogValue implements slog.LogValuer, so fields attached with With below this
+// layer are logged as structured attributes. See LogValue for the layout.
+func (e *stackError) LogValue() slog.Value {
+	return LogValue(e)
+}
+
+// Format implements fmt.Formatter.
+// The %s and %v verbs print the error message, %q prints it quoted, and %+v
+// prints the message and stack trace of every layer in the wrap chain.
+func (e *stackError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if state.Flag('+') {
+			fmt.Fprint(state, e.msg)
+			formatFrames(state, e.StackTrace())
+			formatCause(state, e.cause, false)
+			return
+		}
+		fmt.Fprint(state, e.Error())
+	case 's':
+		fmt.Fprint(state, e.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	}
+}
+
+// formatCause writes cause in the verbose %+v layout.
+// Causes that implement fmt.Formatter format themselves. Other causes are printed
+// once by message and then looked through, so that stack traces recorded further
+// down the chain, for example below a fmt.Errorf layer, are still printed.
+// Causes that wrap several errors print each of them in turn instead of their
+// own message. The printed flag reports whether the message has already been written.
+func formatCause(state fmt.State, cause error, printed bool) {
+	for cause != nil {
+		if _, ok := cause.(fmt.Formatter); ok {
+			fmt.Fprintf(state, "\n%+v", cause)
+			return
+		}
+		if x, ok := cause.(interface{ Unwrap() []error }); ok {
+			for _, child := range x.Unwrap() {
+				formatCause(state, child, false)
+			}
+			return
+		}
+		if !printed {
+			fmt.Fprintf(state, "\n%s", cause.Error())
+			printed = true
+		}
+
+		x, ok := cause.(interface{ Unwrap() error })
+		if !ok {
+			return
+		}
+		cause = x.Unwrap()
+	}
+}
+
+// New returns an error with the given message that records the call stack.
+// Each call returns a distinct error, so the result can be used as a sentinel
+// and compared with errors.Is.
+//
+// The stack trace is printed with the %+v verb and can be disabled globally
+// with SetStackTraces.
+//
+// Example:
+//
+//	err := errors.New("connection refused")
+//	fmt.Printf("%+v\n", err) // message followed by the stack trace
+func New(message string) error {
+	return &stackError{
+		msg:   message,
+		stack: callers(2),
+	}
+}
+
+// Wrap returns an error that annotates err with a message and records the call stack.
+// The returned error unwraps to err, so errors.Is, errors.As and Handle still see
+// the original error. If err is nil, Wrap returns nil.
+//
+// Printing the result with %+v shows the message and stack trace of every layer
+// created by New, Wrap or Wrapf, including layers below other wrappers such as
+// fmt.Errorf.
+//
+// Example:
+//
+//	if err := db.Ping(); err != nil {
+//	    return errors.Wrap(err, "ping database")
+//	}
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{
+		msg:   message,
+		cause: err,
+		stack: callers(2),
+	}
+}
+
+// Wrapf returns an error that annotates err with a formatted message and records
+// the call stack. It behaves like Wrap, and returns nil if err is nil.
+//
+// Example:
+//
+//	if err := os.Remove(path); err != nil {
+//	    return errors.Wrapf(err, "remove %s", path)
+//	}
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{
+		msg:   fmt.Sprintf(format, args...),
+		cause: err,
+		stack: callers(2),
+	}
+}
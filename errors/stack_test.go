@@ -0,0 +1,170 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stackTracer is implemented by errors that record a call stack.
+type stackTracer interface {
+	StackTrace() []runtime.Frame
+}
+
+func TestNew(t *testing.T) {
+	t.Run("New returns error with message", func(t *testing.T) {
+		err := New("connection refused")
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("New returns distinct errors", func(t *testing.T) {
+		err1 := New("same message")
+		err2 := New("same message")
+		assert.NotErrorIs(t, err1, err2)
+		assert.ErrorIs(t, err1, err1)
+	})
+
+	t.Run("New records caller", func(t *testing.T) {
+		err := New("with stack")
+
+		var st stackTracer
+		require.ErrorAs(t, err, &st)
+		frames := st.StackTrace()
+		require.NotEmpty(t, frames)
+		assert.Contains(t, frames[0].Function, "TestNew")
+		assert.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"))
+	})
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("Wrap with nil error", func(t *testing.T) {
+		assert.NoError(t, Wrap(nil, "context"))
+		assert.NoError(t, Wrapf(nil, "context %d", 1))
+	})
+
+	t.Run("Wrap prefixes message", func(t *testing.T) {
+		err := Wrap(io.EOF, "read config")
+		assert.EqualError(t, err, "read config: EOF")
+	})
+
+	t.Run("Wrapf formats message", func(t *testing.T) {
+		err := Wrapf(io.EOF, "read %s at line %d", "config.yaml", 12)
+		assert.EqualError(t, err, "read config.yaml at line 12: EOF")
+	})
+
+	t.Run("Wrap works with errors.Is and errors.As", func(t *testing.T) {
+		customErr := &CustomError{Code: 500, Message: "internal"}
+		err := Wrapf(Wrap(customErr, "layer1"), "layer%d", 2)
+
+		require.ErrorIs(t, err, customErr)
+		var target *CustomError
+		require.ErrorAs(t, err, &target)
+		assert.Same(t, customErr, target)
+	})
+
+	t.Run("Wrap works with Handle", func(t *testing.T) {
+		handled, result := Handle(Wrap(New("timeout"), "call service"),
+			OnSentinel(io.EOF, func(e error) error { return nil }),
+			OnMessage("timeout", func(e error) error { return errors.New("handled timeout") }),
+		)
+		assert.True(t, handled)
+		require.EqualError(t, result, "handled timeout")
+
+		handled, result = Handle(Wrap(&ValidationError{Field: "email"}, "validate"),
+			OnType(func(e *ValidationError) error { return fmt.Errorf("invalid %s", e.Field) }),
+		)
+		assert.True(t, handled)
+		assert.EqualError(t, result, "invalid email")
+	})
+}
+
+func TestStackErrorFormat(t *testing.T) {
+	err := Wrap(fmt.Errorf("dial: %w", New("connection refused")), "load user")
+
+	t.Run("Format with %s and %v", func(t *testing.T) {
+		assert.Equal(t, "load user: dial: connection refused", fmt.Sprintf("%s", err))
+		assert.Equal(t, "load user: dial: connection refused", fmt.Sprintf("%v", err))
+	})
+
+	t.Run("Format with %q", func(t *testing.T) {
+		assert.Equal(t, `"load user: dial: connection refused"`, fmt.Sprintf("%q", err))
+	})
+
+	t.Run("Format with %+v prints every layer", func(t *testing.T) {
+		inner := New("connection refused")
+		outer := Wrap(inner, "load user")
+
+		output := fmt.Sprintf("%+v", outer)
+		lines := strings.Split(output, "\n")
+		assert.Equal(t, "load user", lines[0])
+		assert.Contains(t, lines[1], "TestStackErrorFormat")
+		assert.Contains(t, lines[2], "stack_test.go:")
+		assert.Contains(t, output, "\nconnection refused\n")
+		assert.Equal(t, 2, strings.Count(output, "stack_test.go:"))
+	})
+
+	t.Run("Format with %+v looks through fmt.Errorf layers", func(t *testing.T) {
+		output := fmt.Sprintf("%+v", err)
+		assert.True(t, strings.HasPrefix(output, "load user\n"))
+		assert.Contains(t, output, "\ndial: connection refused\nconnection refused\n")
+		assert.Equal(t, 2, strings.Count(output, "stack_test.go:"))
+	})
+
+	t.Run("Format with %+v looks through joined errors", func(t *testing.T) {
+		joined := Wrap(errors.Join(New("first"), io.EOF), "batch")
+		output := fmt.Sprintf("%+v", joined)
+		assert.True(t, strings.HasPrefix(output, "batch\n"))
+		assert.Contains(t, output, "\nfirst\n")
+		assert.True(t, strings.HasSuffix(output, "\nEOF"))
+		assert.Equal(t, 1, strings.Count(output, "first"))
+		assert.Equal(t, 1, strings.Count(output, "EOF"))
+		assert.Equal(t, 2, strings.Count(output, "stack_test.go:"))
+	})
+
+	t.Run("Format with %+v prints plain causes", func(t *testing.T) {
+		output := fmt.Sprintf("%+v", Wrap(io.EOF, "read"))
+		assert.True(t, strings.HasPrefix(output, "read\n"))
+		assert.True(t, strings.HasSuffix(output, "\nEOF"))
+	})
+}
+
+func TestSetStackTraces(t *testing.T) {
+	SetStackTraces(false)
+	t.Cleanup(func() { SetStackTraces(true) })
+
+	err := Wrap(New("no stack"), "wrapped")
+	var st stackTracer
+	require.ErrorAs(t, err, &st)
+	assert.Empty(t, st.StackTrace())
+	assert.Equal(t, "wrapped\nno stack", fmt.Sprintf("%+v", err))
+
+	SetStackTraces(true)
+	err = New("with stack")
+	require.ErrorAs(t, err, &st)
+	assert.NotEmpty(t, st.StackTrace())
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("with stack", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = New("benchmark")
+		}
+	})
+
+	b.Run("without stack", func(b *testing.B) {
+		SetStackTraces(false)
+		defer SetStackTraces(true)
+
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = New("benchmark")
+		}
+	})
+}
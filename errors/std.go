@@ -0,0 +1,22 @@
+package errors
+
+import "errors"
+
+// Is reports whether any error in err's tree matches target.
+// It is errors.Is from the standard library, provided so that this package can
+// be imported in place of the standard errors package.
+var Is = errors.Is
+
+// As finds the first error in err's tree that matches target, and if one is found,
+// sets target to that error value and returns true.
+// It is errors.As from the standard library.
+var As = errors.As
+
+// Unwrap returns the result of calling the Unwrap method on err, if err's type
+// contains an Unwrap method returning error. Otherwise, Unwrap returns nil.
+// It is errors.Unwrap from the standard library.
+var Unwrap = errors.Unwrap
+
+// Join returns an error that wraps the given errors, discarding nil errors.
+// It is errors.Join from the standard library.
+var Join = errors.Join
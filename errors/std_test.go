@@ -0,0 +1,23 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardLibraryFunctions(t *testing.T) {
+	customErr := &CustomError{Code: 404, Message: "not found"}
+	err := Wrap(Join(io.EOF, customErr), "batch")
+
+	assert.True(t, Is(err, io.EOF))
+	assert.False(t, Is(err, io.ErrClosedPipe))
+
+	var target *CustomError
+	assert.True(t, As(err, &target))
+	assert.Same(t, customErr, target)
+
+	assert.EqualError(t, Unwrap(err), "EOF\ncustom error 404: not found")
+	assert.NoError(t, Join(nil, nil))
+}
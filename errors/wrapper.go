@@ -0,0 +1,31 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// wrapper is embedded by the errors that annotate another error without
+// changing it, such as those returned by With, WithKind and WithTemporary. It keeps
+// the message, formatting and log value of the wrapped error and unwraps to it.
+type wrapper struct {
+	err error
+}
+
+func (e *wrapper) Error() string {
+	return e.err.Error()
+}
+
+func (e *wrapper) Unwrap() error {
+	return e.err
+}
+
+// Format implements fmt.Formatter by formatting the wrapped error.
+func (e *wrapper) Format(state fmt.State, verb rune) {
+	fmt.Fprintf(state, fmt.FormatString(state, verb), e.err)
+}
+
+// LogValue implements slog.LogValuer. See LogValue for the layout.
+func (e *wrapper) LogValue() slog.Value {
+	return LogValue(e)
+}
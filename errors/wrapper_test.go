@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapper(t *testing.T) {
+	wrappers := map[string]func(error) error{
+		"WithKind":       func(err error) error { return WithKind(err, NotFound) },
+		"WithTemporary":  func(err error) error { return WithTemporary(err, true) },
+		"WithRetryAfter": func(err error) error { return WithRetryAfter(err, time.Second) },
+		"WithPublic":     func(err error) error { return WithPublic(err, DefaultPublicMessage) },
+	}
+	for name, wrap := range wrappers {
+		t.Run(name+" keeps the wrapped error", func(t *testing.T) {
+			inner := With(fmt.Errorf("read: %w", io.EOF), "id", 7)
+			err := wrap(inner)
+
+			require.ErrorIs(t, err, io.EOF)
+			assert.Equal(t, "read: EOF", err.Error())
+			assert.Equal(t, fmt.Sprintf("%+v", inner), fmt.Sprintf("%+v", err))
+			assert.Equal(t, `"read: EOF"`, fmt.Sprintf("%q", err))
+			assert.Equal(t, LogValue(inner).String(), slog.AnyValue(err).Resolve().String())
+		})
+	}
+}